@@ -0,0 +1,341 @@
+package ai
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+)
+
+// ----- TARGETING --------------------------------------------------------------------
+
+// targetWeight multiplies placements covering hits that are not sunk yet, so the
+// ship that was hit is finished before hunting continues.
+const targetWeight = 50
+
+// shipShapes are the shapes of the ships by their size, including the bent ones
+// (every shape accepted by util.ValidateFleet).
+var shipShapes = util.ShipShapes(4)
+
+// Targeting is the engine that tracks the shots on the opponent's board and ranks
+// the remaining cells by probability density over all legal placements of the
+// ships that are still afloat (straight and bent). After a hit it switches from
+// hunt to target mode.
+//
+// shots - Results of the shots (one of models.Result* constants) by cell.
+//
+// sunk - Cells of the ships that were sunk.
+//
+// excluded - Cells next to the sunk ships (they can't contain ships).
+//
+// remaining - Sizes of the ships that are still afloat.
+//
+// random - Source of randomness for breaking ties (nil means first best cell).
+type Targeting struct {
+	shots     map[[2]int]string
+	sunk      map[[2]int]bool
+	excluded  map[[2]int]bool
+	remaining []int
+	random    *rand.Rand
+}
+
+// NewTargeting creates the targeting engine for the classic fleet.
+//
+//	Arguments:
+//
+// random - Source of randomness for breaking ties (can be nil).
+//
+//	Returns:
+//
+// *Targeting - Engine without any shots recorded.
+func NewTargeting(random *rand.Rand) *Targeting {
+	remaining := []int{}
+	for size := 4; size >= 1; size-- {
+		for i := 0; i < util.ClassicFleet[size]; i++ {
+			remaining = append(remaining, size)
+		}
+	}
+
+	return &Targeting{
+		shots:     map[[2]int]string{},
+		sunk:      map[[2]int]bool{},
+		excluded:  map[[2]int]bool{},
+		remaining: remaining,
+		random:    random,
+	}
+}
+
+// Record tells the engine about the result of the shot. After "sunk" the whole ship
+// is reconstructed from adjacent hits and its surrounding is excluded. Cell that
+// was already recorded is not changed anymore (repeated record does nothing).
+//
+//	Arguments:
+//
+// coord - Coordinate of the shot (eg. "B10").
+//
+// result - One of models.Result* constants.
+//
+//	Returns:
+//
+// error - If coordinate or result is invalid.
+func (t *Targeting) Record(coord string, result string) error {
+	c, err := parseCell(coord)
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case models.ResultMiss, models.ResultHit, models.ResultSunk:
+	default:
+		return errors.New("unknown result " + result)
+	}
+	if _, shot := t.shots[c]; shot {
+		return nil
+	}
+
+	t.shots[c] = result
+	if result == models.ResultSunk {
+		t.sinkShip(c)
+	}
+	return nil
+}
+
+// Available checks if it makes sense to fire at the coordinate (it was not shot
+// yet and it is not next to a sunk ship).
+//
+//	Arguments:
+//
+// coord - Coordinate to check (eg. "B10").
+//
+//	Returns:
+//
+// bool - True if cell can still contain a ship.
+func (t *Targeting) Available(coord string) bool {
+	c, err := parseCell(coord)
+	if err != nil {
+		return false
+	}
+	_, shot := t.shots[c]
+	return !shot && !t.excluded[c]
+}
+
+// Excluded returns coordinates of the cells next to the sunk ships that were not shot.
+func (t *Targeting) Excluded() []string {
+	coords := []string{}
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if _, shot := t.shots[[2]int{x, y}]; t.excluded[[2]int{x, y}] && !shot {
+				coords = append(coords, util.IntegersToCoord(x, y))
+			}
+		}
+	}
+	return coords
+}
+
+// Sunk returns coordinates of the cells of the sunk ships.
+func (t *Targeting) Sunk() []string {
+	coords := []string{}
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if t.sunk[[2]int{x, y}] {
+				coords = append(coords, util.IntegersToCoord(x, y))
+			}
+		}
+	}
+	return coords
+}
+
+// Remaining returns sizes of the ships that are still afloat.
+func (t *Targeting) Remaining() []int {
+	return append([]int{}, t.remaining...)
+}
+
+// Density returns the weight of every cell (indexed as [x-1][y-1]). The higher the
+// weight, the more placements of remaining ships cover the cell.
+//
+//	Returns:
+//
+// [10][10]int - Weights of the cells (0 for cells that can't be a ship).
+func (t *Targeting) Density() [10][10]int {
+	density := [10][10]int{}
+	targetMode := t.openHits() > 0
+
+	//Counting every size once, weighted by number of ships of that size
+	sizes := map[int]int{}
+	for _, size := range t.remaining {
+		sizes[size]++
+	}
+
+	for size, count := range sizes {
+		for x := 1; x <= 10; x++ {
+			for y := 1; y <= 10; y++ {
+				for _, shape := range shipShapes[size] {
+					cells, ok := t.placement(x, y, shape)
+					if !ok {
+						continue
+					}
+
+					covered := 0
+					for _, c := range cells {
+						if t.shots[c] == models.ResultHit {
+							covered++
+						}
+					}
+					if targetMode && covered == 0 {
+						continue
+					}
+
+					weight := count
+					if covered > 0 {
+						weight *= targetWeight * covered
+					}
+					for _, c := range cells {
+						if _, shot := t.shots[c]; !shot {
+							density[c[0]-1][c[1]-1] += weight
+						}
+					}
+				}
+			}
+		}
+	}
+	return density
+}
+
+// Ranking returns all the available cells sorted from the most probable one.
+//
+//	Returns:
+//
+// []string - Coordinates of the cells (eg. "B10").
+func (t *Targeting) Ranking() []string {
+	density := t.Density()
+	cells := [][2]int{}
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if t.Available(util.IntegersToCoord(x, y)) {
+				cells = append(cells, [2]int{x, y})
+			}
+		}
+	}
+
+	if t.random != nil {
+		t.random.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	}
+	sort.SliceStable(cells, func(i, j int) bool {
+		return density[cells[i][0]-1][cells[i][1]-1] > density[cells[j][0]-1][cells[j][1]-1]
+	})
+
+	ranking := make([]string, len(cells))
+	for i, c := range cells {
+		ranking[i] = util.IntegersToCoord(c[0], c[1])
+	}
+	return ranking
+}
+
+// Suggest returns the best cell to fire at.
+//
+//	Returns:
+//
+// string - Coordinate of the cell (eg. "B10").
+//
+// error - If there is no cell left to fire at.
+func (t *Targeting) Suggest() (string, error) {
+	ranking := t.Ranking()
+	if len(ranking) == 0 {
+		return "", errors.New("there is no cell left to fire at")
+	}
+	return ranking[0], nil
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// placement returns cells of the ship of the given shape placed at (x, y) if such
+// placement is possible.
+//
+//	Returns:
+//
+// [][2]int - Cells of the ship.
+//
+// bool - False if ship leaves the board or covers missed, sunk or excluded cells.
+func (t *Targeting) placement(x int, y int, shape [][2]int) ([][2]int, bool) {
+	cells := make([][2]int, len(shape))
+	for i := range cells {
+		c := [2]int{x + shape[i][0], y + shape[i][1]}
+		if c[0] > 10 || c[1] > 10 || t.shots[c] == models.ResultMiss || t.sunk[c] || t.excluded[c] {
+			return nil, false
+		}
+		cells[i] = c
+	}
+	return cells, true
+}
+
+// sinkShip marks the ship containing the cell as sunk, excludes its surrounding
+// and removes its size from the remaining ships.
+func (t *Targeting) sinkShip(start [2]int) {
+	ship := [][2]int{start}
+	visited := map[[2]int]bool{start: true}
+	for i := 0; i < len(ship); i++ {
+		for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			next := [2]int{ship[i][0] + d[0], ship[i][1] + d[1]}
+			if result := t.shots[next]; !visited[next] && (result == models.ResultHit || result == models.ResultSunk) && !t.sunk[next] {
+				visited[next] = true
+				ship = append(ship, next)
+			}
+		}
+	}
+
+	for _, c := range ship {
+		t.sunk[c] = true
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				next := [2]int{c[0] + dx, c[1] + dy}
+				if next[0] >= 1 && next[0] <= 10 && next[1] >= 1 && next[1] <= 10 && !visited[next] {
+					t.excluded[next] = true
+				}
+			}
+		}
+	}
+
+	//Removing the size of the sunk ship (or the closest one, if hits of the ships were joined)
+	best := -1
+	for i, size := range t.remaining {
+		if best == -1 || abs(size-len(ship)) < abs(t.remaining[best]-len(ship)) {
+			best = i
+		}
+	}
+	if best != -1 {
+		t.remaining = append(t.remaining[:best], t.remaining[best+1:]...)
+	}
+}
+
+// openHits counts hits that do not belong to sunk ships.
+func (t *Targeting) openHits() int {
+	open := 0
+	for c, result := range t.shots {
+		if result == models.ResultHit && !t.sunk[c] {
+			open++
+		}
+	}
+	return open
+}
+
+// parseCell translates coordinate into (x, y) cell inside of the board.
+func parseCell(coord string) ([2]int, error) {
+	x, y, err := util.CoordToIntegers(coord)
+	if err != nil {
+		return [2]int{}, err
+	}
+	if x < 1 || x > 10 || y < 1 || y > 10 {
+		return [2]int{}, errors.New("coordinate " + coord + " is outside of the board")
+	}
+	return [2]int{x, y}, nil
+}
+
+// abs returns absolute value of the integer.
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
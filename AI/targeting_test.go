@@ -0,0 +1,219 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+)
+
+// shot is a single recorded shot of the test.
+type shot struct {
+	coord  string
+	result string
+}
+
+func TestTargetingRecord(t *testing.T) {
+	tests := []struct {
+		name      string
+		shots     []shot
+		sunk      []string
+		excluded  []string
+		remaining []int
+	}{
+		{
+			name:      "miss and hit",
+			shots:     []shot{{"A1", models.ResultMiss}, {"C3", models.ResultHit}},
+			sunk:      []string{},
+			excluded:  []string{},
+			remaining: []int{4, 3, 3, 2, 2, 2, 1, 1, 1, 1},
+		},
+		{
+			name:      "single mast in the corner",
+			shots:     []shot{{"A1", models.ResultSunk}},
+			sunk:      []string{"A1"},
+			excluded:  []string{"A2", "B1", "B2"},
+			remaining: []int{4, 3, 3, 2, 2, 2, 1, 1, 1},
+		},
+		{
+			name:      "straight ship",
+			shots:     []shot{{"B2", models.ResultHit}, {"C2", models.ResultSunk}},
+			sunk:      []string{"B2", "C2"},
+			excluded:  []string{"A1", "A2", "A3", "B1", "B3", "C1", "C3", "D1", "D2", "D3"},
+			remaining: []int{4, 3, 3, 2, 2, 1, 1, 1, 1},
+		},
+		{
+			name:      "bent ship",
+			shots:     []shot{{"A1", models.ResultHit}, {"A2", models.ResultHit}, {"B2", models.ResultSunk}},
+			sunk:      []string{"A1", "A2", "B2"},
+			excluded:  []string{"A3", "B1", "B3", "C1", "C2", "C3"},
+			remaining: []int{4, 3, 2, 2, 2, 1, 1, 1, 1},
+		},
+		{
+			name:      "too long ship removes the closest size",
+			shots:     []shot{{"A1", models.ResultHit}, {"A2", models.ResultHit}, {"A3", models.ResultHit}, {"A4", models.ResultHit}, {"A5", models.ResultSunk}},
+			sunk:      []string{"A1", "A2", "A3", "A4", "A5"},
+			excluded:  []string{"A6", "B1", "B2", "B3", "B4", "B5", "B6"},
+			remaining: []int{3, 3, 2, 2, 2, 1, 1, 1, 1},
+		},
+		{
+			name:      "sunk ship is not joined with the other one",
+			shots:     []shot{{"A1", models.ResultSunk}, {"A3", models.ResultHit}, {"A4", models.ResultSunk}},
+			sunk:      []string{"A1", "A3", "A4"},
+			excluded:  []string{"A2", "A5", "B1", "B2", "B3", "B4", "B5"},
+			remaining: []int{4, 3, 3, 2, 2, 1, 1, 1},
+		},
+		{
+			name:      "repeated sunk does nothing",
+			shots:     []shot{{"A1", models.ResultSunk}, {"A1", models.ResultSunk}},
+			sunk:      []string{"A1"},
+			excluded:  []string{"A2", "B1", "B2"},
+			remaining: []int{4, 3, 3, 2, 2, 2, 1, 1, 1},
+		},
+		{
+			name:      "repeated record keeps the first result",
+			shots:     []shot{{"E5", models.ResultMiss}, {"E5", models.ResultSunk}},
+			sunk:      []string{},
+			excluded:  []string{},
+			remaining: []int{4, 3, 3, 2, 2, 2, 1, 1, 1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targeting := NewTargeting(nil)
+			for _, s := range test.shots {
+				if err := targeting.Record(s.coord, s.result); err != nil {
+					t.Fatalf("Record(%s, %s) = %v", s.coord, s.result, err)
+				}
+			}
+
+			if sunk := targeting.Sunk(); !reflect.DeepEqual(sunk, test.sunk) {
+				t.Errorf("Sunk() = %v, want %v", sunk, test.sunk)
+			}
+			if excluded := targeting.Excluded(); !reflect.DeepEqual(excluded, test.excluded) {
+				t.Errorf("Excluded() = %v, want %v", excluded, test.excluded)
+			}
+			if remaining := targeting.Remaining(); !reflect.DeepEqual(remaining, test.remaining) {
+				t.Errorf("Remaining() = %v, want %v", remaining, test.remaining)
+			}
+			for _, s := range test.shots {
+				if targeting.Available(s.coord) {
+					t.Errorf("Available(%s) = true after the shot", s.coord)
+				}
+			}
+			for _, coord := range test.excluded {
+				if targeting.Available(coord) {
+					t.Errorf("Available(%s) = true next to the sunk ship", coord)
+				}
+			}
+		})
+	}
+}
+
+func TestTargetingRecordErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		coord  string
+		result string
+	}{
+		{"unknown result", "A1", "splash"},
+		{"outside of the board", "K1", models.ResultMiss},
+		{"too far down", "A11", models.ResultHit},
+		{"not a coordinate", "?", models.ResultMiss},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targeting := NewTargeting(nil)
+			if err := targeting.Record(test.coord, test.result); err == nil {
+				t.Fatalf("Record(%q, %q) = nil, want error", test.coord, test.result)
+			}
+			if len(targeting.Ranking()) != 100 {
+				t.Fatalf("invalid record changed the board")
+			}
+		})
+	}
+}
+
+func TestTargetingSuggest(t *testing.T) {
+	tests := []struct {
+		name  string
+		shots []shot
+		want  []string
+	}{
+		{
+			name:  "finishing the hit ship",
+			shots: []shot{{"E5", models.ResultHit}},
+			want:  []string{"D5", "F5", "E4", "E6"},
+		},
+		{
+			name:  "next to two hits (ship can be bent)",
+			shots: []shot{{"E5", models.ResultHit}, {"F5", models.ResultHit}},
+			want:  []string{"D5", "G5", "E4", "E6", "F4", "F6"},
+		},
+		{
+			name:  "corner hit",
+			shots: []shot{{"A1", models.ResultHit}, {"B1", models.ResultMiss}},
+			want:  []string{"A2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targeting := NewTargeting(nil)
+			for _, s := range test.shots {
+				if err := targeting.Record(s.coord, s.result); err != nil {
+					t.Fatalf("Record(%s, %s) = %v", s.coord, s.result, err)
+				}
+			}
+
+			suggestion, err := targeting.Suggest()
+			if err != nil {
+				t.Fatalf("Suggest() error = %v", err)
+			}
+			found := false
+			for _, coord := range test.want {
+				found = found || coord == suggestion
+			}
+			if !found {
+				t.Fatalf("Suggest() = %s, want one of %v", suggestion, test.want)
+			}
+		})
+	}
+}
+
+func TestTargetingBentPlacements(t *testing.T) {
+	targeting := NewTargeting(nil)
+	if err := targeting.Record("E5", models.ResultHit); err != nil {
+		t.Fatal(err)
+	}
+
+	//Cells diagonal to the hit can be covered only by the bent ships
+	density := targeting.Density()
+	for _, coord := range []string{"D4", "F4", "D6", "F6"} {
+		x, y, _ := util.CoordToIntegers(coord)
+		if density[x-1][y-1] == 0 {
+			t.Errorf("Density() of %s = 0, want bent ships counted", coord)
+		}
+	}
+	//Cells two steps away diagonally can't be covered by the ship of size 4 through E5
+	if density[2][2] != 0 {
+		t.Errorf("Density() of C3 = %d, want 0", density[2][2])
+	}
+}
+
+func TestTargetingNoCellLeft(t *testing.T) {
+	targeting := NewTargeting(nil)
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if err := targeting.Record(util.IntegersToCoord(x, y), models.ResultMiss); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if coord, err := targeting.Suggest(); err == nil {
+		t.Fatalf("Suggest() = %s, want error on the full board", coord)
+	}
+}
@@ -0,0 +1,298 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	ai "sea-of-pirates/AI"
+	models "sea-of-pirates/Models"
+	source "sea-of-pirates/Source"
+	util "sea-of-pirates/util"
+)
+
+// ----- BOT     ----------------------------------------------------------------------
+
+// DefaultPollInterval is the time between two checks of the game status.
+const DefaultPollInterval = time.Second
+
+// Strategy chooses the shots of the headless player. It is implemented by ai.Targeting.
+type Strategy interface {
+	// Suggest returns the coordinate to fire at next.
+	Suggest() (string, error)
+	// Record tells the strategy about the result of the shot.
+	Record(coord string, result string) error
+}
+
+// Options configure the headless game.
+//
+// Request - Profile and fleet of the player. If coords are empty, random fleet is used.
+//
+// Fleet - Constraints of the random fleet.
+//
+// Strategy - Strategy of the shots. If nil, ai.Targeting is used.
+//
+// Log - Destination of the progress log. If nil, os.Stdout is used.
+//
+// PollInterval - Time between two checks of the game status (0 means DefaultPollInterval).
+type Options struct {
+	Request      models.StartGameRequest
+	Fleet        util.FleetOptions
+	Strategy     Strategy
+	Log          io.Writer
+	PollInterval time.Duration
+}
+
+// Move is a single shot of the game.
+//
+// Coord - Coordinate of the shot (eg. "B10").
+//
+// Result - One of models.Result* constants.
+type Move struct {
+	Coord  string `json:"coord"`
+	Result string `json:"result"`
+}
+
+// Result is the summary of the finished headless game.
+//
+// Nick, Opponent - Nicks of both players.
+//
+// Outcome - One of models.Last* constants.
+//
+// Fleet - Coordinates of the player's ships.
+//
+// Moves - Shots of the player in order.
+//
+// OppShots - Shots of the opponent in order.
+//
+// Hits, Sunk - Number of player's shots that hit or sunk a ship (sunk counts as hit).
+//
+// Started, Duration - When the game has started and how long it lasted.
+type Result struct {
+	Nick     string        `json:"nick"`
+	Opponent string        `json:"opponent"`
+	Outcome  string        `json:"outcome"`
+	Fleet    []string      `json:"fleet"`
+	Moves    []Move        `json:"moves"`
+	OppShots []string      `json:"opp_shots"`
+	Hits     int           `json:"hits"`
+	Sunk     int           `json:"sunk"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Won checks if the player has won the game.
+func (r Result) Won() bool {
+	return r.Outcome == models.LastWin
+}
+
+// Play runs the whole game without any GUI: starts it, waits for the opponent,
+// fires using the strategy and returns as soon as the game has ended.
+//
+//	Arguments:
+//
+// ctx - Context that stops the game (game is not abandoned on the server).
+//
+// backend - Backend that runs the game (remote server or local engine).
+//
+// options - Profile of the player, strategy and logging.
+//
+//	Returns:
+//
+// Result - Summary of the game (filled as far as the game went, also on error).
+//
+// error - If the game could not be started or finished.
+func Play(ctx context.Context, backend source.Backend, options Options) (Result, error) {
+	if options.Log == nil {
+		options.Log = os.Stdout
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if options.Strategy == nil {
+		options.Strategy = ai.NewTargeting(nil)
+	}
+	logger := log.New(options.Log, "", log.Ltime)
+
+	request := options.Request
+	if len(request.Coords) == 0 {
+		coords, err := util.RandomFleet(options.Fleet)
+		if err != nil {
+			return Result{}, err
+		}
+		request.Coords = coords
+	}
+
+	result := Result{Nick: request.Nick, Fleet: request.Coords, Moves: []Move{}, OppShots: []string{}}
+
+	//Starting the game and waiting for the opponent
+	logger.Printf("starting the game as %q", request.Nick)
+	if err := backend.StartGame(ctx, request); err != nil {
+		return result, fmt.Errorf("start game: %w", err)
+	}
+
+	status, err := waitForStatus(ctx, backend, options.PollInterval, func(s models.GameStatus) bool {
+		return s.GameStatus == models.StatusInProgress || s.GameStatus == models.StatusEnded
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Opponent = status.Opponent
+	result.Started = time.Now()
+	logger.Printf("game started against %q", status.Opponent)
+
+	//Firing until the game ends
+	for status.GameStatus != models.StatusEnded {
+		if status.ShouldFire {
+			move, err := fire(ctx, backend, options.Strategy)
+			if err != nil {
+				return result, err
+			}
+			result.Moves = append(result.Moves, move)
+			if move.Result != models.ResultMiss {
+				result.Hits++
+			}
+			if move.Result == models.ResultSunk {
+				result.Sunk++
+			}
+			logger.Printf("fired at %s: %s", move.Coord, move.Result)
+		} else if !sleepContext(ctx, options.PollInterval) {
+			return result, ctx.Err()
+		}
+
+		status, err = backend.GameStatus(ctx)
+		if err != nil {
+			return result, fmt.Errorf("game status: %w", err)
+		}
+		for _, shot := range status.OppShots[min(len(result.OppShots), len(status.OppShots)):] {
+			logger.Printf("opponent fired at %s", shot)
+		}
+		result.OppShots = append([]string{}, status.OppShots...)
+	}
+
+	result.Outcome = status.LastGameStatus
+	result.Duration = time.Since(result.Started)
+	logger.Printf("game ended: %s after %d shots (%d hits, %d sunk)", result.Outcome, len(result.Moves), result.Hits, result.Sunk)
+	return result, nil
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// fire asks the strategy for the shot, fires it and tells the strategy the result.
+//
+//	Returns:
+//
+// Move - Fired shot with its result.
+//
+// error - If strategy has no shot or firing failed.
+func fire(ctx context.Context, backend source.Backend, strategy Strategy) (Move, error) {
+	coord, err := strategy.Suggest()
+	if err != nil {
+		return Move{}, fmt.Errorf("strategy: %w", err)
+	}
+
+	result, err := backend.Fire(ctx, coord)
+	if err != nil {
+		return Move{}, fmt.Errorf("fire at %s: %w", coord, err)
+	}
+	if err := strategy.Record(coord, result.Result); err != nil {
+		return Move{}, fmt.Errorf("strategy: %w", err)
+	}
+	return Move{Coord: coord, Result: result.Result}, nil
+}
+
+// waitForStatus polls the game status until it is accepted.
+//
+//	Arguments:
+//
+// accept - Function that decides if waiting is over.
+//
+//	Returns:
+//
+// models.GameStatus - Accepted status.
+//
+// error - If status could not be retrieved or ctx was cancelled.
+func waitForStatus(ctx context.Context, backend source.Backend, interval time.Duration, accept func(models.GameStatus) bool) (models.GameStatus, error) {
+	for {
+		status, err := backend.GameStatus(ctx)
+		if err != nil {
+			return status, fmt.Errorf("game status: %w", err)
+		}
+		if accept(status) {
+			return status, nil
+		}
+		if !sleepContext(ctx, interval) {
+			return status, ctx.Err()
+		}
+	}
+}
+
+// sleepContext waits for the given time.
+//
+//	Returns:
+//
+// bool - False if ctx was cancelled before.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// ----- RECORD  ----------------------------------------------------------------------
+
+// SaveResult writes the result of the game as JSON, so it can be replayed later.
+//
+//	Arguments:
+//
+// path - Path of the file (overwritten if exists).
+//
+// result - Result of the game.
+//
+//	Returns:
+//
+// error - If file can't be written.
+func SaveResult(path string, result Result) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadResult reads the result of the game saved by SaveResult.
+//
+//	Arguments:
+//
+// path - Path of the file.
+//
+//	Returns:
+//
+// Result - Result of the game.
+//
+// error - If file can't be read or is not a valid result.
+func LoadResult(path string) (Result, error) {
+	var result Result
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("%s is not a game record: %w", path, err)
+	}
+	for _, move := range result.Moves {
+		if _, _, err := util.CoordToIntegers(move.Coord); err != nil {
+			return result, fmt.Errorf("%s contains invalid move %q: %w", path, move.Coord, err)
+		}
+	}
+	return result, nil
+}
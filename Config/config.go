@@ -0,0 +1,389 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	http "sea-of-pirates/HTTP"
+	util "sea-of-pirates/util"
+)
+
+// ----- CONFIG  ----------------------------------------------------------------------
+
+// FileName is the name of the config file inside of the config directory.
+const FileName = "config.json"
+
+// AppDir is the directory of the program inside of the user's config directory
+// (eg. ~/.config/sea-of-pirates on Linux, respecting XDG_CONFIG_HOME).
+const AppDir = "sea-of-pirates"
+
+// Environment variables that override the config file.
+const (
+	EnvConfig       = "SEA_OF_PIRATES_CONFIG"
+	EnvNick         = "SEA_OF_PIRATES_NICK"
+	EnvDesc         = "SEA_OF_PIRATES_DESC"
+	EnvTarget       = "SEA_OF_PIRATES_TARGET"
+	EnvWPBot        = "SEA_OF_PIRATES_BOT"
+	EnvServer       = "SEA_OF_PIRATES_SERVER"
+	EnvTimeout      = "SEA_OF_PIRATES_TIMEOUT"
+	EnvPollInterval = "SEA_OF_PIRATES_POLL_INTERVAL"
+)
+
+// Config is the persistent configuration of the program.
+//
+// Profile - Player's nick, description and opponent preference.
+//
+// Fleet - Default fleet (empty means none, placement starts with the empty board).
+//
+// RandomFleet - Constraints of the random fleets (used when ships are not placed manually).
+//
+// Server - Address of the game server and timeouts.
+//
+// Colors - Colors of the boards.
+type Config struct {
+	Profile     Profile     `json:"profile"`
+	Fleet       []string    `json:"fleet,omitempty"`
+	RandomFleet RandomFleet `json:"random_fleet"`
+	Server      Server      `json:"server"`
+	Colors      Colors      `json:"colors"`
+}
+
+// Profile describes the player.
+//
+// Nick - The nick of the Player.
+//
+// Desc - Description of the Player.
+//
+// TargetNick - Nick of required opponent (can be empty).
+//
+// WPBot - Should it use WP bot as AI opponent.
+type Profile struct {
+	Nick       string `json:"nick"`
+	Desc       string `json:"desc"`
+	TargetNick string `json:"target_nick,omitempty"`
+	WPBot      bool   `json:"wpbot"`
+}
+
+// RandomFleet configures the generator of the random fleets.
+//
+// AvoidEdges - Keep ships away from the edges of the board (at most 4 cells on edges).
+//
+// Corner - Cluster ships in the corner: "A1", "J1", "A10" or "J10" (empty for none).
+type RandomFleet struct {
+	AvoidEdges bool   `json:"avoid_edges,omitempty"`
+	Corner     string `json:"corner,omitempty"`
+}
+
+// Options translates the configuration into the options of the generator.
+func (r RandomFleet) Options() util.FleetOptions {
+	return util.FleetOptions{AvoidEdges: r.AvoidEdges, Corner: r.Corner}
+}
+
+// Server configures the connection with the game server.
+//
+// URL - Base URL of the game server API.
+//
+// Timeout - Limit of the single request.
+//
+// PollInterval - Time between two checks of the game status.
+type Server struct {
+	URL          string   `json:"url"`
+	Timeout      Duration `json:"timeout"`
+	PollInterval Duration `json:"poll_interval"`
+}
+
+// Colors of the boards written as "#RRGGBB" (empty means the default color).
+type Colors struct {
+	Ship string `json:"ship,omitempty"`
+	Hit  string `json:"hit,omitempty"`
+	Miss string `json:"miss,omitempty"`
+	Hint string `json:"hint,omitempty"`
+	Sunk string `json:"sunk,omitempty"`
+}
+
+// Duration is time.Duration written in the config as text (eg. "10s", "500ms").
+type Duration time.Duration
+
+// MarshalJSON writes the duration as text.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON reads the duration written as text.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be text like \"10s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default returns the configuration used when there is no config file.
+//
+//	Returns:
+//
+// Config - Dummy profile, default server and default colors.
+func Default() Config {
+	dummy := util.JSONGetDummy()
+	return Config{
+		Profile: Profile{
+			Nick:  dummy["nick"].(string),
+			Desc:  dummy["desc"].(string),
+			WPBot: dummy["wpbot"].(bool),
+		},
+		Server: Server{
+			URL:          http.DefaultServerURL,
+			Timeout:      Duration(http.DefaultTimeout),
+			PollInterval: Duration(time.Second),
+		},
+	}
+}
+
+// DefaultPath returns the path of the config file: SEA_OF_PIRATES_CONFIG if set,
+// otherwise config.json in the user's config directory.
+//
+//	Returns:
+//
+// string - Path of the config file.
+//
+// error - If the user's config directory is unknown.
+func DefaultPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AppDir, FileName), nil
+}
+
+// Load reads the config file and applies the environment variables on top of it.
+//
+// Missing file at the default path is not an error (defaults are used), missing
+// file given explicitly is.
+//
+//	Arguments:
+//
+// path - Path of the config file (empty means DefaultPath).
+//
+//	Returns:
+//
+// Config - Validated configuration.
+//
+// error - If file can't be read or configuration is not valid (readable list of problems).
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return cfg, err
+		}
+		explicit = os.Getenv(EnvConfig) != ""
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+	case err != nil:
+		return cfg, fmt.Errorf("config: %w", err)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			return cfg, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.ApplyEnv(); err != nil {
+		return cfg, err
+	}
+	cfg.Server.URL = ServerURL(cfg.Server.URL)
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ServerURL adds the missing "/" at the end of the server URL, so paths of the API
+// can be joined to it (eg. "http://localhost:8080/api" + "game").
+//
+//	Arguments:
+//
+// serverURL - URL of the game server API.
+//
+//	Returns:
+//
+// string - URL ending with "/" (empty URL is not changed).
+func ServerURL(serverURL string) string {
+	if serverURL == "" || strings.HasSuffix(serverURL, "/") {
+		return serverURL
+	}
+	return serverURL + "/"
+}
+
+// Save writes the configuration as indented JSON, creating the directory if needed.
+//
+//	Arguments:
+//
+// path - Path of the config file (empty means DefaultPath).
+//
+//	Returns:
+//
+// error - If configuration is not valid or file can't be written.
+func (c Config) Save(path string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ApplyEnv overrides the configuration with SEA_OF_PIRATES_* environment variables.
+//
+//	Returns:
+//
+// error - If value of the variable can't be read.
+func (c *Config) ApplyEnv() error {
+	texts := map[string]*string{
+		EnvNick:   &c.Profile.Nick,
+		EnvDesc:   &c.Profile.Desc,
+		EnvTarget: &c.Profile.TargetNick,
+		EnvServer: &c.Server.URL,
+	}
+	for name, field := range texts {
+		if value, ok := os.LookupEnv(name); ok {
+			*field = value
+		}
+	}
+
+	//Target means playing against the player, unless the bot is asked for explicitly
+	if _, ok := os.LookupEnv(EnvTarget); ok && c.Profile.TargetNick != "" {
+		c.Profile.WPBot = false
+	}
+	if value, ok := os.LookupEnv(EnvWPBot); ok {
+		wpbot, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not true or false", EnvWPBot, value)
+		}
+		c.Profile.WPBot = wpbot
+	}
+
+	durations := map[string]*Duration{
+		EnvTimeout:      &c.Server.Timeout,
+		EnvPollInterval: &c.Server.PollInterval,
+	}
+	for name, field := range durations {
+		if value, ok := os.LookupEnv(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a duration like \"10s\"", name, value)
+			}
+			*field = Duration(d)
+		}
+	}
+	return nil
+}
+
+// Validate checks the whole configuration.
+//
+//	Returns:
+//
+// error - Every problem found, one per line (nil if configuration is valid).
+func (c Config) Validate() error {
+	problems := []string{}
+	add := func(field string, format string, args ...any) {
+		problems = append(problems, field+": "+fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(c.Profile.Nick) == "" {
+		add("profile.nick", "must not be empty")
+	}
+	if c.Profile.TargetNick != "" && c.Profile.WPBot {
+		add("profile", "target_nick and wpbot can't be used together")
+	}
+
+	if len(c.Fleet) > 0 {
+		if err := util.ValidateFleet(c.Fleet); err != nil {
+			add("fleet", "%v", err)
+		}
+	}
+
+	if err := c.RandomFleet.Options().Validate(); err != nil {
+		add("random_fleet.corner", "%v", err)
+	}
+
+	if u, err := url.Parse(c.Server.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add("server.url", "%q is not a http(s) URL", c.Server.URL)
+	} else if !strings.HasSuffix(c.Server.URL, "/") {
+		add("server.url", "%q must end with \"/\" (eg. %q)", c.Server.URL, ServerURL(c.Server.URL))
+	}
+	if c.Server.Timeout <= 0 {
+		add("server.timeout", "must be positive")
+	}
+	if c.Server.PollInterval <= 0 {
+		add("server.poll_interval", "must be positive")
+	}
+
+	colors := map[string]string{"ship": c.Colors.Ship, "hit": c.Colors.Hit, "miss": c.Colors.Miss, "hint": c.Colors.Hint, "sunk": c.Colors.Sunk}
+	for _, name := range []string{"ship", "hit", "miss", "hint", "sunk"} {
+		if _, _, _, err := ParseColor(colors[name]); colors[name] != "" && err != nil {
+			add("colors."+name, "%v", err)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid configuration:\n  " + strings.Join(problems, "\n  "))
+}
+
+// ParseColor reads the color written as "#RRGGBB".
+//
+//	Arguments:
+//
+// color - Written color.
+//
+//	Returns:
+//
+// uint8, uint8, uint8 - Red, green and blue values.
+//
+// error - If color is not written as "#RRGGBB".
+func ParseColor(color string) (uint8, uint8, uint8, error) {
+	if len(color) != 7 || color[0] != '#' {
+		return 0, 0, 0, fmt.Errorf("%q is not a color like \"#1E90FF\"", color)
+	}
+	value, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("%q is not a color like \"#1E90FF\"", color)
+	}
+	return uint8(value >> 16), uint8(value >> 8), uint8(value), nil
+}
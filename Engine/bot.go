@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"math/rand"
+)
+
+// ----- BOT     ----------------------------------------------------------------------
+
+// BotNick is the nick of the built-in bot.
+const BotNick = "Local_Bot"
+
+// BotDesc is the description of the built-in bot.
+const BotDesc = "Built-in bot of Sea Of Pirates"
+
+// BotFleet is the layout of ships used by the built-in bot when random fleet can't be generated.
+var BotFleet = []string{
+	"A1", "A2", "A3", "A4",
+	"C1", "D1", "E1",
+	"J1", "J2", "J3",
+	"C4", "C5",
+	"F4", "G4",
+	"J6", "J7",
+	"A8", "D8", "G10", "J10",
+}
+
+// Bot is a simple opponent that hunts randomly and finishes ships after a hit.
+//
+// random - Source of randomness for the shots.
+//
+// shots - Cells already fired at.
+//
+// targets - Cells next to the hits that should be fired at first.
+type Bot struct {
+	random  *rand.Rand
+	shots   map[Cell]bool
+	targets []Cell
+}
+
+// NewBot creates the bot.
+//
+//	Arguments:
+//
+// random - Source of randomness for the shots.
+func NewBot(random *rand.Rand) *Bot {
+	return &Bot{random: random, shots: map[Cell]bool{}}
+}
+
+// NextShot chooses the cell that bot fires at.
+//
+//	Returns:
+//
+// Cell - Cell that was never fired at before.
+func (b *Bot) NextShot() Cell {
+	// Finishing the ship that was hit before
+	for len(b.targets) > 0 {
+		target := b.targets[len(b.targets)-1]
+		b.targets = b.targets[:len(b.targets)-1]
+		if !b.shots[target] {
+			b.shots[target] = true
+			return target
+		}
+	}
+
+	// Hunting randomly
+	free := []Cell{}
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if !b.shots[Cell{x, y}] {
+				free = append(free, Cell{x, y})
+			}
+		}
+	}
+	shot := free[b.random.Intn(len(free))]
+	b.shots[shot] = true
+	return shot
+}
+
+// Learn tells the bot what was the result of its shot.
+//
+//	Arguments:
+//
+// shot - Cell that bot fired at.
+//
+// hit - Was there a ship.
+func (b *Bot) Learn(shot Cell, hit bool) {
+	if !hit {
+		return
+	}
+
+	for _, next := range shot.Neighbours() {
+		if !b.shots[next] {
+			b.targets = append(b.targets, next)
+		}
+	}
+}
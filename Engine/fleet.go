@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"fmt"
+
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+)
+
+// ----- FLEET   ----------------------------------------------------------------------
+
+// FleetSize is the number of cells of the classic fleet (4 + 2*3 + 3*2 + 4*1).
+const FleetSize = 20
+
+// Cell is a single field of the board as a pair of numbers (column, row), both from 1 to 10.
+type Cell [2]int
+
+// Fleet keeps ships of one player together with the shots received from the opponent.
+//
+// ships - All the cells occupied by ships.
+//
+// shots - All the cells that opponent has already fired at.
+type Fleet struct {
+	ships map[Cell]bool
+	shots map[Cell]bool
+}
+
+// NewFleet creates the fleet from the list of coordinates.
+//
+//	Arguments:
+//
+// coords - Coordinates of the ships (eg. "A1", "B10").
+//
+//	Returns:
+//
+// *Fleet - Recently created fleet.
+//
+// error - If coordinates are not a legal fleet, returns util.FleetErrors.
+func NewFleet(coords []string) (*Fleet, error) {
+	if err := util.ValidateFleet(coords); err != nil {
+		return nil, err
+	}
+
+	f := &Fleet{ships: map[Cell]bool{}, shots: map[Cell]bool{}}
+	for _, coord := range coords {
+		c, err := ParseCell(coord)
+		if err != nil {
+			return nil, err
+		}
+		f.ships[c] = true
+	}
+	return f, nil
+}
+
+// Fire adjudicates the shot at the given cell.
+//
+//	Arguments:
+//
+// c - Cell that is being fired at.
+//
+//	Returns:
+//
+// string - One of models.Result* constants.
+func (f *Fleet) Fire(c Cell) string {
+	f.shots[c] = true
+	if !f.ships[c] {
+		return models.ResultMiss
+	}
+
+	for _, part := range f.ShipAt(c) {
+		if !f.shots[part] {
+			return models.ResultHit
+		}
+	}
+	return models.ResultSunk
+}
+
+// Shot checks if the cell was already fired at.
+func (f *Fleet) Shot(c Cell) bool {
+	return f.shots[c]
+}
+
+// ShipAt returns all the cells of the ship that occupies the given cell.
+//
+//	Arguments:
+//
+// c - Cell that belongs to the ship.
+//
+//	Returns:
+//
+// []Cell - Cells of the whole ship (empty if there is no ship at all).
+func (f *Fleet) ShipAt(c Cell) []Cell {
+	if !f.ships[c] {
+		return []Cell{}
+	}
+
+	visited := map[Cell]bool{c: true}
+	queue := []Cell{c}
+	for i := 0; i < len(queue); i++ {
+		for _, next := range queue[i].Neighbours() {
+			if f.ships[next] && !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return queue
+}
+
+// AllSunk checks if every cell of the fleet was hit.
+func (f *Fleet) AllSunk() bool {
+	for c := range f.ships {
+		if !f.shots[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// Coords returns the ships of the fleet as list of coordinates.
+func (f *Fleet) Coords() []string {
+	coords := []string{}
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			if f.ships[Cell{x, y}] {
+				coords = append(coords, Cell{x, y}.Coord())
+			}
+		}
+	}
+	return coords
+}
+
+// ----- COORDS  ----------------------------------------------------------------------
+
+// ParseCell translates coordinate (eg. "B10") into the cell.
+//
+//	Arguments:
+//
+// coord - Coordinate to translate.
+//
+//	Returns:
+//
+// Cell - Translated cell.
+//
+// error - If coordinate is outside of the board or can't be read.
+func ParseCell(coord string) (Cell, error) {
+	x, y, err := util.CoordToIntegers(coord)
+	if err != nil {
+		return Cell{}, fmt.Errorf("invalid coordinate %q: %w", coord, err)
+	}
+	if x < 1 || x > 10 || y < 1 || y > 10 {
+		return Cell{}, fmt.Errorf("coordinate %q is outside of the board", coord)
+	}
+	return Cell{x, y}, nil
+}
+
+// Coord translates the cell into coordinate (eg. "B10").
+func (c Cell) Coord() string {
+	return util.IntegersToCoord(c[0], c[1])
+}
+
+// Inside checks if the cell is on the 10x10 board.
+func (c Cell) Inside() bool {
+	return c[0] >= 1 && c[0] <= 10 && c[1] >= 1 && c[1] <= 10
+}
+
+// Neighbours returns orthogonal neighbours of the cell that are on the board.
+func (c Cell) Neighbours() []Cell {
+	neighbours := []Cell{}
+	for _, next := range []Cell{{c[0] - 1, c[1]}, {c[0] + 1, c[1]}, {c[0], c[1] - 1}, {c[0], c[1] + 1}} {
+		if next.Inside() {
+			neighbours = append(neighbours, next)
+		}
+	}
+	return neighbours
+}
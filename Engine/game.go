@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"errors"
+
+	models "sea-of-pirates/Models"
+)
+
+// ----- GAME    ----------------------------------------------------------------------
+
+// Errors returned by Game.Fire.
+var (
+	ErrGameEnded   = errors.New("game is not in progress")
+	ErrNotYourTurn = errors.New("it is not your turn")
+	ErrAlreadyShot = errors.New("field was already shot")
+)
+
+// Game owns fleets of both sides, adjudicates shots and determines the victory.
+// Sides are numbered 0 and 1.
+//
+// fleets - Fleets of both sides.
+//
+// shots - Shots made by each of the sides.
+//
+// turn - Side that should fire.
+//
+// ended - Is the game already over.
+//
+// winner - Side that won (valid only after the end).
+type Game struct {
+	fleets [2]*Fleet
+	shots  [2][]string
+	turn   int
+	ended  bool
+	winner int
+}
+
+// NewGame creates the game between two fleets.
+//
+//	Arguments:
+//
+// first - Coordinates of the ships of side 0.
+//
+// second - Coordinates of the ships of side 1.
+//
+// turn - Side that fires first (0 or 1).
+//
+//	Returns:
+//
+// *Game - Recently created game.
+//
+// error - If any of the fleets is not legal.
+func NewGame(first []string, second []string, turn int) (*Game, error) {
+	firstFleet, err := NewFleet(first)
+	if err != nil {
+		return nil, err
+	}
+	secondFleet, err := NewFleet(second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Game{fleets: [2]*Fleet{firstFleet, secondFleet}, turn: turn % 2}, nil
+}
+
+// Fire adjudicates the shot of the given side. Hit or sunk keeps the turn, miss
+// passes it to the opponent. Sinking the last ship ends the game.
+//
+//	Arguments:
+//
+// shooter - Side that fires.
+//
+// coord - Coordinate to fire at (eg. "B10").
+//
+//	Returns:
+//
+// string - One of models.Result* constants.
+//
+// error - If game is over, it is not the shooter's turn, coordinate is invalid
+// or it was already shot (the turn is kept, nothing is recorded).
+func (g *Game) Fire(shooter int, coord string) (string, error) {
+	if g.ended {
+		return "", ErrGameEnded
+	}
+	if g.turn != shooter {
+		return "", ErrNotYourTurn
+	}
+
+	c, err := ParseCell(coord)
+	if err != nil {
+		return "", err
+	}
+
+	target := g.fleets[1-shooter]
+	if target.Shot(c) {
+		return "", ErrAlreadyShot
+	}
+
+	g.shots[shooter] = append(g.shots[shooter], c.Coord())
+	result := target.Fire(c)
+
+	if target.AllSunk() {
+		g.ended = true
+		g.winner = shooter
+	} else if result == models.ResultMiss {
+		g.turn = 1 - shooter
+	}
+	return result, nil
+}
+
+// Forfeit ends the game with the loss of the given side.
+//
+//	Arguments:
+//
+// loser - Side that gives up (or runs out of time).
+func (g *Game) Forfeit(loser int) {
+	if g.ended {
+		return
+	}
+	g.ended = true
+	g.winner = 1 - loser
+}
+
+// Turn returns the side that should fire.
+func (g *Game) Turn() int {
+	return g.turn
+}
+
+// Ended checks if the game is over.
+func (g *Game) Ended() bool {
+	return g.ended
+}
+
+// Winner returns the side that won (valid only after the end).
+func (g *Game) Winner() int {
+	return g.winner
+}
+
+// Shots returns a copy of the shots made by the given side.
+func (g *Game) Shots(side int) []string {
+	return append([]string{}, g.shots[side]...)
+}
+
+// Fleet returns the fleet of the given side.
+func (g *Game) Fleet(side int) *Fleet {
+	return g.fleets[side]
+}
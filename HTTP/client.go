@@ -0,0 +1,189 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ----- CLIENT  ----------------------------------------------------------------------
+
+// DefaultServerURL is the URL of the game server that is used when no other is given.
+const DefaultServerURL = "https://go-pjatk-server.fly.dev/api/"
+
+// DefaultTimeout is the time after which single HTTP request is abandoned.
+const DefaultTimeout = 10 * time.Second
+
+// Client is an instantiable game API client. Every client holds its own server URL
+// and authorization token, so many sessions can live inside of one process.
+//
+// baseURL - URL of the game server (with "/api/" at the end).
+//
+// token - Authorization token retrieved by StartGame.
+//
+// httpClient - Client used for sending requests.
+//
+// timeout - Time after which single HTTP request is abandoned.
+//
+// retry - Policy of retrying transient failures.
+type Client struct {
+	mutex      sync.RWMutex
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	timeout    time.Duration
+	retry      RetryPolicy
+}
+
+// defaultClient is the client used by package functions (GameStatus, Fire, ...).
+var defaultClient = NewClient(DefaultServerURL)
+
+// NewClient creates new client for the given server.
+//
+//	Arguments:
+//
+// baseURL - URL of the game server. If empty, DefaultServerURL is used.
+//
+//	Returns:
+//
+// *Client - Recently created client without authorization token.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultServerURL
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy(),
+	}
+}
+
+// DefaultClient returns the client that is used by package functions.
+//
+//	Returns:
+//
+// *Client - Pointer at default client of the package.
+func DefaultClient() *Client {
+	return defaultClient
+}
+
+// ----- DEADLINES --------------------------------------------------------------------
+
+// callTimeoutKey is a key of the context value with timeout for a single call.
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns context that overrides the client timeout for calls made with it.
+//
+//	Arguments:
+//
+// ctx - Parent context.
+//
+// timeout - Time after which the call is abandoned (0 means no limit at all).
+//
+//	Returns:
+//
+// context.Context - Context carrying the timeout for the call.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// withDeadline limits the given context with the timeout of the call. Timeout set by
+// WithCallTimeout has priority over the timeout of the client.
+//
+//	Arguments:
+//
+// ctx - Context of the call.
+//
+//	Returns:
+//
+// context.Context - Context limited by the timeout.
+//
+// context.CancelFunc - Function that releases resources of the context.
+func (c *Client) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timeout := c.Timeout()
+	if callTimeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		timeout = callTimeout
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ----- GETTERS ----------------------------------------------------------------------
+
+// ServerURL returns the URL of the game server used by client.
+func (c *Client) ServerURL() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.baseURL
+}
+
+// Token returns the authorization token of the client (empty before StartGame).
+func (c *Client) Token() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.token
+}
+
+// Timeout returns the time after which single HTTP request is abandoned.
+func (c *Client) Timeout() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.timeout
+}
+
+// RetryPolicy returns the policy of retrying transient failures.
+func (c *Client) RetryPolicy() RetryPolicy {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.retry
+}
+
+// ----- SETTERS ----------------------------------------------------------------------
+
+// SetServerURL changes the URL of the game server used by client.
+func (c *Client) SetServerURL(URL string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.baseURL = URL
+}
+
+// SetToken changes the authorization token of the client.
+func (c *Client) SetToken(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = token
+}
+
+// SetTimeout changes the time after which single HTTP request is abandoned.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.timeout = timeout
+}
+
+// SetHTTPClient changes the client used for sending requests (eg. with custom transport).
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.httpClient = httpClient
+}
+
+// SetRetryPolicy changes the policy of retrying transient failures.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	c.retry = policy
+}
@@ -2,18 +2,19 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+
+	models "sea-of-pirates/Models"
 )
 
 // ----- GLOBAL  ----------------------------------------------------------------------
 
-var token string
-var serverURL string = "https://go-pjatk-server.fly.dev/api/"
-
 const (
 	GET    = "GET"
 	POST   = "POST"
@@ -42,35 +43,51 @@ type Response struct {
 
 // GameStatus is a http function for handling game status HTTP request
 //
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
 //	Returns:
 //
 // Response - All in one structure that have neccessary info of HTTP Request
-func GameStatus() Response {
-	return call(GET, "game", nil, nil, true)
+func (c *Client) GameStatus(ctx context.Context) (models.GameStatus, Response) {
+	var status models.GameStatus
+	resp := c.call(ctx, GET, "game", nil, nil, true)
+	decode(&resp, &status)
+	return status, resp
 }
 
 // StartGame is a http function for sending HTTP request of beginning the game.
-// It also retrives authorization token!
+// It also retrives authorization token and keeps it inside of the client!
 //
 //	Arguments:
 //
-// jsonParameters - Map of JSON data parameter of request
+// ctx - Context that can cancel the request or limit its time.
+//
+// request - Typed body of the request (coords, nick, desc, ...)
 //
 //	Returns:
 //
 // Response - All in one structure that have neccessary info of HTTP Request
-func StartGame(jsonParameters map[string]any) Response {
-	resp := call(POST, "game", nil, jsonParameters, false)
+func (c *Client) StartGame(ctx context.Context, request models.StartGameRequest) Response {
+	if err := request.Validate(); err != nil {
+		return Response{nil, []byte{}, -1, err}
+	}
+
+	resp := c.call(ctx, POST, "game", nil, request, false)
+	checkStatus(&resp)
 	//Check if some error occured
 	if resp.Err != nil {
 		return resp
 	}
 
 	//Retrieving authorization token
-	token = resp.Header.Get("X-Auth-Token")
+	token := resp.Header.Get("X-Auth-Token")
 	if token == "" {
 		resp.Err = errors.New("can't retrieve authorization token from request")
+		return resp
 	}
+	c.SetToken(token)
 
 	//All good
 	return resp
@@ -78,54 +95,332 @@ func StartGame(jsonParameters map[string]any) Response {
 
 // GetMyGameBoard is a http function for handling getting the game board in HTTP request
 //
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
 //	Returns:
 //
 // Response - All in one structure that have neccessary info of HTTP Request
-func GetMyGameBoard() Response {
-	return call(GET, "game/board", nil, nil, true)
+func (c *Client) GetMyGameBoard(ctx context.Context) (models.BoardResponse, Response) {
+	var board models.BoardResponse
+	resp := c.call(ctx, GET, "game/board", nil, nil, true)
+	decode(&resp, &board)
+	return board, resp
 }
 
 // Fire is a http function for sending HTTP request of firing to specific coordinate
 //
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// coord - Coordinate to fire at (eg. "B10").
+//
 //	Returns:
 //
+// models.FireResult - Result of the shot (hit / miss / sunk).
+//
 // Response - All in one structure that have neccessary info of HTTP Request
-func Fire(coord string) Response {
-	jParam := map[string]any{"coord": coord}
-	return call(POST, "game/fire", nil, jParam, true)
+func (c *Client) Fire(ctx context.Context, coord string) (models.FireResult, Response) {
+	var result models.FireResult
+	request := models.FireRequest{Coord: coord}
+	if err := request.Validate(); err != nil {
+		return result, Response{nil, []byte{}, -1, err}
+	}
+
+	resp := c.call(ctx, POST, "game/fire", nil, request, true)
+	decode(&resp, &result)
+	return result, resp
 }
 
 // --- OPTIONAL ----------------
-//func GiveUp() error
-//func GetMyAndOpponentDesc() error
-//func RefreshSession() error
-//func Lobby() error
-//func Stats() error
-//func StatsOfPlayer(nick string) error
+
+// GiveUp is a http function for sending HTTP request of abandoning the current game.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) GiveUp(ctx context.Context) Response {
+	resp := c.call(ctx, DELETE, "game/abandon", nil, nil, true)
+	checkStatus(&resp)
+	return resp
+}
+
+// GetMyAndOpponentDesc is a http function for retrieving nicks and descriptions
+// of both the player and the opponent.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) GetMyAndOpponentDesc(ctx context.Context) (models.DescResponse, Response) {
+	var desc models.DescResponse
+	resp := c.call(ctx, GET, "game/desc", nil, nil, true)
+	decode(&resp, &desc)
+	return desc, resp
+}
+
+// RefreshSession is a http function for refreshing the session of the player,
+// so the server will not drop it while waiting in the lobby.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) RefreshSession(ctx context.Context) Response {
+	resp := c.call(ctx, GET, "game/refresh", nil, nil, true)
+	checkStatus(&resp)
+	return resp
+}
+
+// Lobby is a http function for retrieving the list of players waiting for a game.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) Lobby(ctx context.Context) (models.LobbyResponse, Response) {
+	var lobby models.LobbyResponse
+	resp := c.call(ctx, GET, "lobby", nil, nil, false)
+	decode(&resp, &lobby)
+	return lobby, resp
+}
+
+// Stats is a http function for retrieving statistics of the top players.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) Stats(ctx context.Context) (models.StatsResponse, Response) {
+	var stats models.StatsResponse
+	resp := c.call(ctx, GET, "stats", nil, nil, false)
+	decode(&resp, &stats)
+	return stats, resp
+}
+
+// StatsOfPlayer is a http function for retrieving statistics of the specific player.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// nick - Nick of the player whose statistics are required.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) StatsOfPlayer(ctx context.Context, nick string) (models.PlayerStatsResponse, Response) {
+	var stats models.PlayerStatsResponse
+	resp := c.call(ctx, GET, "stats/"+url.PathEscape(nick), nil, nil, false)
+	decode(&resp, &stats)
+	return stats, resp
+}
+
+// ----- DEFAULT ----------------------------------------------------------------------
+
+// Package functions below are kept for compatibility and delegate to DefaultClient().
+
+// GameStatus retrieves the status of the game using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.GameStatus - Status of the game.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func GameStatus(ctx context.Context) (models.GameStatus, Response) {
+	return defaultClient.GameStatus(ctx)
+}
+
+// StartGame begins the game using the default client (token is kept inside of it).
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// request - Typed body of the request (coords, nick, desc, ...)
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func StartGame(ctx context.Context, request models.StartGameRequest) Response {
+	return defaultClient.StartGame(ctx, request)
+}
+
+// GetMyGameBoard retrieves the ships of the player using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.BoardResponse - Coordinates of the player's ships.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func GetMyGameBoard(ctx context.Context) (models.BoardResponse, Response) {
+	return defaultClient.GetMyGameBoard(ctx)
+}
+
+// Fire shoots at the coordinate using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// coord - Coordinate to fire at (eg. "B10").
+//
+//	Returns:
+//
+// models.FireResult - Result of the shot (hit / miss / sunk).
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func Fire(ctx context.Context, coord string) (models.FireResult, Response) {
+	return defaultClient.Fire(ctx, coord)
+}
+
+// GiveUp abandons the current game using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func GiveUp(ctx context.Context) Response {
+	return defaultClient.GiveUp(ctx)
+}
+
+// GetMyAndOpponentDesc retrieves nicks and descriptions of both players using
+// the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.DescResponse - Nicks and descriptions of both players.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func GetMyAndOpponentDesc(ctx context.Context) (models.DescResponse, Response) {
+	return defaultClient.GetMyAndOpponentDesc(ctx)
+}
+
+// RefreshSession keeps the session of the player alive using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func RefreshSession(ctx context.Context) Response {
+	return defaultClient.RefreshSession(ctx)
+}
+
+// Lobby retrieves the players waiting for a game using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.LobbyResponse - Waiting players.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func Lobby(ctx context.Context) (models.LobbyResponse, Response) {
+	return defaultClient.Lobby(ctx)
+}
+
+// Stats retrieves statistics of the top players using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.StatsResponse - Statistics of the top players.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func Stats(ctx context.Context) (models.StatsResponse, Response) {
+	return defaultClient.Stats(ctx)
+}
+
+// StatsOfPlayer retrieves statistics of the specific player using the default client.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// nick - Nick of the player whose statistics are required.
+//
+//	Returns:
+//
+// models.PlayerStatsResponse - Statistics of the player.
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func StatsOfPlayer(ctx context.Context, nick string) (models.PlayerStatsResponse, Response) {
+	return defaultClient.StatsOfPlayer(ctx, nick)
+}
 
 // ----- NETWORK ----------------------------------------------------------------------
 
-// Call is a all-in-one function for GET, POST and DELETE HTTP Requests
+// Call is a all-in-one function for GET, POST and DELETE HTTP Requests.
+// Transient failures are retried according to the retry policy of the client.
 //
 //	Arguments:
 //
+// ctx - Context that can cancel the request or limit its time.
+//
 // TYPE - Type of HTTP Request (GET / POST / DELETE)
 //
 // addURL - URL to add to serverURL
 //
 // parameters - Map of parameters to be inside of URL after "?"
 //
-// jsonParameters - Map of JSON data parameter of request
+// jsonParameters - Data of the request that will be sent as JSON (can be nil)
 //
 // includeToken - Should token be includen into HTTP request
 //
 //	Returns:
 //
 // Response - All in one structure that have neccessary info of HTTP Request
-func call(TYPE string, addURL string, parameters map[string]string, jsonParameters map[string]any, includeToken bool) Response {
+func (c *Client) call(ctx context.Context, TYPE string, addURL string, parameters map[string]string, jsonParameters any, includeToken bool) Response {
+
+	switch TYPE {
+	case GET, POST, DELETE:
+	default:
+		return Response{nil, []byte{}, -1, errors.New("unsupported type of HTTP request: " + TYPE)}
+	}
 
 	// Creating URL with parameters
-	finalUrl := urlWithParameters(parameters, addURL)
+	finalUrl := urlWithParameters(c.ServerURL(), parameters, addURL)
 
 	// Creating JSON data to send
 	json_data, err := json.Marshal(jsonParameters)
@@ -133,70 +428,87 @@ func call(TYPE string, addURL string, parameters map[string]string, jsonParamete
 		return Response{nil, []byte{}, -1, err}
 	}
 
-	// Preparing variables
-	var resp *http.Response
-
-	var header http.Header
-	var body []byte
-	var statusCode int
-	var errHttp error
-
-	var req *http.Request
+	// Nil context is allowed, as in withDeadline
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	switch TYPE {
-	case GET:
+	policy := c.RetryPolicy()
+	var resp Response
+	for attempt := 1; ; attempt++ {
+		resp = c.send(ctx, TYPE, finalUrl, json_data, includeToken)
 
-		// Making the GET request
-		req, err = http.NewRequest(GET, finalUrl, bytes.NewBuffer(json_data))
-		if err != nil {
-			errHttp = err
+		// Checking if another attempt makes sense
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(TYPE, resp) {
+			return resp
 		}
 
-	case POST:
-
-		// Making the POST request
-		req, err = http.NewRequest(POST, finalUrl, bytes.NewBuffer(json_data))
-		if err != nil {
-			errHttp = err
+		// Waiting before the next attempt
+		if !sleepContext(ctx, policy.delay(attempt, resp.Header)) {
+			return resp
 		}
+	}
+}
+
+// send makes a single attempt of the HTTP request.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// TYPE - Type of HTTP Request (GET / POST / DELETE)
+//
+// finalUrl - Full URL of the request.
+//
+// json_data - Body of the request.
+//
+// includeToken - Should token be includen into HTTP request
+//
+//	Returns:
+//
+// Response - All in one structure that have neccessary info of HTTP Request
+func (c *Client) send(ctx context.Context, TYPE string, finalUrl string, json_data []byte, includeToken bool) Response {
 
-	case DELETE:
+	// Limiting the time of the request
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
 
-		// Making the DELETE request
-		req, err = http.NewRequest(DELETE, finalUrl, bytes.NewBuffer(json_data))
-		if err != nil {
-			errHttp = err
-		}
+	// Making the request
+	req, err := http.NewRequestWithContext(ctx, TYPE, finalUrl, bytes.NewReader(json_data))
+	if err != nil {
+		return Response{nil, []byte{}, -1, err}
 	}
 
 	// Adding information to header
 	req.Header.Add("Content-Type", "application/json")
 	if includeToken {
-		req.Header.Add("X-AUTH-TOKEN", token)
+		req.Header.Add("X-AUTH-TOKEN", c.Token())
 	}
 
 	//Making an HTTP request
-	resp, errHttp = http.DefaultClient.Do(req)
+	c.mutex.RLock()
+	httpClient := c.httpClient
+	c.mutex.RUnlock()
+
+	resp, errHttp := httpClient.Do(req)
 	if errHttp != nil {
-		return Response{nil, []byte{}, -1, err}
+		return Response{nil, []byte{}, -1, errHttp}
 	}
 
 	// Reading the header and body
-	body, errHttp = io.ReadAll(resp.Body)
-	header = resp.Header
-	statusCode = resp.StatusCode
-
+	body, errHttp := io.ReadAll(resp.Body)
 	resp.Body.Close()
 
 	//Packing all information into one single response
-	packagedResponse := Response{header, body, statusCode, errHttp}
-	return packagedResponse
+	return Response{resp.Header, body, resp.StatusCode, errHttp}
 }
 
 // Function for creating final URL with parameters after "?"
 //
 //	Arguments:
 //
+// serverURL - URL of the server to start from.
+//
 // parameters - parameters to add after "?".
 //
 // addURL - text to add after serverURL.
@@ -204,7 +516,7 @@ func call(TYPE string, addURL string, parameters map[string]string, jsonParamete
 //	Returns:
 //
 // finalURL - modified URL string.
-func urlWithParameters(parameters map[string]string, addURL string) string {
+func urlWithParameters(serverURL string, parameters map[string]string, addURL string) string {
 	URLParameters := url.Values{}
 	for key, value := range parameters {
 		URLParameters.Add(key, value)
@@ -213,6 +525,66 @@ func urlWithParameters(parameters map[string]string, addURL string) string {
 	return finalUrl
 }
 
+// ----- DECODING ---------------------------------------------------------------------
+
+// StatusError is an error returned when server answers with unsuccessful status code.
+//
+// StatusCode - Status code of the HTTP response.
+//
+// Message - Message that server sent together with the status code.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("server answered with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("server answered with status %d: %s", e.StatusCode, e.Message)
+}
+
+// checkStatus sets Response.Err to StatusError if status code is not successful.
+//
+//	Arguments:
+//
+// resp - Pointer at response that needs to be checked.
+func checkStatus(resp *Response) {
+	if resp.Err != nil || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return
+	}
+
+	var body models.ErrorResponse
+	json.Unmarshal(resp.Body, &body)
+	message := body.Message
+	if message == "" {
+		message = body.Error
+	}
+	resp.Err = &StatusError{resp.StatusCode, message}
+}
+
+// decode translates the body of the response into the given model and validates it.
+// Every problem is surfaced through Response.Err.
+//
+//	Arguments:
+//
+// resp - Pointer at response with body to decode.
+//
+// model - Pointer at model where body should be decoded into.
+func decode(resp *Response, model models.Validator) {
+	checkStatus(resp)
+	if resp.Err != nil {
+		return
+	}
+
+	if err := json.Unmarshal(resp.Body, model); err != nil {
+		resp.Err = fmt.Errorf("can't decode response: %w", err)
+		return
+	}
+
+	resp.Err = model.Validate()
+}
+
 // ----- TESTS   ----------------------------------------------------------------------
 
 func TestPackage() string {
@@ -222,15 +594,15 @@ func TestPackage() string {
 // ----- GETTERS ----------------------------------------------------------------------
 
 func GetServerURL() string {
-	return serverURL
+	return defaultClient.ServerURL()
 }
 
 func GetToken() string {
-	return token
+	return defaultClient.Token()
 }
 
 // ----- SETTERS ----------------------------------------------------------------------
 
 func SetServerURL(URL string) {
-	serverURL = URL
+	defaultClient.SetServerURL(URL)
 }
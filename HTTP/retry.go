@@ -0,0 +1,199 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ----- RETRY   ----------------------------------------------------------------------
+
+// RetryPolicy describes how transient failures of the server are retried.
+//
+// MaxAttempts - How many times request is sent at most (1 means no retries).
+//
+// BaseDelay - Delay before the second attempt, doubled with every next attempt.
+//
+// MaxDelay - Upper limit of the delay between attempts.
+//
+// Jitter - Fraction (0-1) of the delay that is randomized to spread the retries.
+//
+// RetryableStatus - Status codes after which idempotent requests are retried.
+//
+// RetryableMethods - Methods that are idempotent and can be retried after any
+// transient failure.
+//
+// SafeStatus - Status codes that guarantee the server did not process the request,
+// so even non-idempotent requests (like Fire) can be retried without double-shooting.
+type RetryPolicy struct {
+	MaxAttempts      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	Jitter           float64
+	RetryableStatus  map[int]bool
+	RetryableMethods map[string]bool
+	SafeStatus       map[int]bool
+}
+
+// DefaultRetryPolicy returns the policy used by every new client.
+//
+//	Returns:
+//
+// RetryPolicy - 4 attempts with exponential backoff from 250ms up to 4s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    4 * time.Second,
+		Jitter:      0.2,
+		RetryableStatus: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusBadGateway:         true,
+			http.StatusServiceUnavailable: true,
+			http.StatusGatewayTimeout:     true,
+		},
+		RetryableMethods: map[string]bool{GET: true, DELETE: true},
+		SafeStatus: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusServiceUnavailable: true,
+		},
+	}
+}
+
+// NoRetryPolicy returns the policy that never retries.
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// shouldRetry decides if request can be sent once again after the given response.
+//
+//	Arguments:
+//
+// method - Type of HTTP Request (GET / POST / DELETE).
+//
+// resp - Response of the last attempt.
+//
+//	Returns:
+//
+// bool - True if another attempt is both useful and safe.
+func (p RetryPolicy) shouldRetry(method string, resp Response) bool {
+	idempotent := p.RetryableMethods[method]
+
+	if resp.Err != nil {
+		// Cancelled by the caller, nothing to retry
+		if errors.Is(resp.Err, context.Canceled) {
+			return false
+		}
+		// Request never left the client, so it is safe for every method
+		if isDialError(resp.Err) {
+			return true
+		}
+		// Connection dropped or timed out, server might have processed the request
+		return idempotent
+	}
+
+	if p.SafeStatus[resp.StatusCode] {
+		return true
+	}
+	return idempotent && p.RetryableStatus[resp.StatusCode]
+}
+
+// delay calculates how long to wait before the next attempt. Retry-After header
+// sent by the server has priority over the exponential backoff, but it is limited
+// by MaxDelay as well.
+//
+//	Arguments:
+//
+// attempt - Number of the attempt that just failed (starting from 1).
+//
+// header - Header of the failed response (can be nil).
+//
+//	Returns:
+//
+// time.Duration - Time to wait before the next attempt.
+func (p RetryPolicy) delay(attempt int, header http.Header) time.Duration {
+	if retryAfter, ok := parseRetryAfter(header); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			retryAfter = p.MaxDelay
+		}
+		return retryAfter
+	}
+
+	wait := p.BaseDelay
+	for i := 1; i < attempt && wait < p.MaxDelay; i++ {
+		wait *= 2
+	}
+	if p.MaxDelay > 0 && wait > p.MaxDelay {
+		wait = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		spread := float64(wait) * p.Jitter
+		wait = time.Duration(float64(wait) - spread + rand.Float64()*2*spread)
+	}
+	return wait
+}
+
+// parseRetryAfter reads Retry-After header given either in seconds or as HTTP date.
+//
+//	Arguments:
+//
+// header - Header of the response (can be nil).
+//
+//	Returns:
+//
+// time.Duration - Time that server asked to wait.
+//
+// bool - False if there is no usable Retry-After header.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// isDialError checks if error happened while connecting, before anything was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleepContext waits for the given time unless context gets cancelled earlier
+// (nil context can't be cancelled).
+//
+//	Returns:
+//
+// bool - True if the whole time passed, false if context was cancelled.
+func sleepContext(ctx context.Context, wait time.Duration) bool {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,266 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	util "sea-of-pirates/util"
+)
+
+// ----- CONSTANTS ---------------------------------------------------------------------
+
+// Values of the game_status field.
+const (
+	StatusNoGame       = "no_game"
+	StatusWaiting      = "waiting"
+	StatusWaitingWPBot = "waiting_wpbot"
+	StatusInProgress   = "game_in_progress"
+	StatusEnded        = "ended"
+)
+
+// Values of the last_game_status field.
+const (
+	LastWin  = "win"
+	LastLose = "lose"
+)
+
+// Values of the result field after firing.
+const (
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+	ResultSunk = "sunk"
+)
+
+// Validator is implemented by every model that can check itself after decoding.
+type Validator interface {
+	Validate() error
+}
+
+// ----- REQUESTS ----------------------------------------------------------------------
+
+// StartGameRequest is a body of the request that begins the game.
+//
+// Coords - Locations of all ships of the player (eg. "A1", "B10").
+//
+// Desc - Description of the Player.
+//
+// Nick - The nick of the Player.
+//
+// TargetNick - Nick of required opponent (can be empty).
+//
+// WPBot - Should it use WP bot as AI opponent.
+type StartGameRequest struct {
+	Coords     []string `json:"coords"`
+	Desc       string   `json:"desc"`
+	Nick       string   `json:"nick"`
+	TargetNick string   `json:"target_nick"`
+	WPBot      bool     `json:"wpbot"`
+}
+
+// Validate checks if request can be sent to the server.
+func (r StartGameRequest) Validate() error {
+	if len(r.Coords) == 0 {
+		return errors.New("start game request: coords can't be empty")
+	}
+	for _, coord := range r.Coords {
+		if err := validateCoord(coord); err != nil {
+			return fmt.Errorf("start game request: %w", err)
+		}
+	}
+	if r.WPBot && r.TargetNick != "" {
+		return errors.New("start game request: can't play against WP bot and target nick at the same time")
+	}
+	return nil
+}
+
+// FireRequest is a body of the request that fires at the given coordinate.
+type FireRequest struct {
+	Coord string `json:"coord"`
+}
+
+// Validate checks if request can be sent to the server.
+func (r FireRequest) Validate() error {
+	if err := validateCoord(r.Coord); err != nil {
+		return fmt.Errorf("fire request: %w", err)
+	}
+	return nil
+}
+
+// ----- RESPONSES ---------------------------------------------------------------------
+
+// GameStatus is a response of the game status request.
+//
+// GameStatus - One of Status* constants.
+//
+// LastGameStatus - Result of the last game (win / lose).
+//
+// Nick - Nick of the player.
+//
+// OppShots - All the shots that opponent made on the player board.
+//
+// Opponent - Nick of the opponent.
+//
+// ShouldFire - Is it player's turn.
+//
+// Timer - Seconds left for the player's move.
+type GameStatus struct {
+	GameStatus     string   `json:"game_status"`
+	LastGameStatus string   `json:"last_game_status"`
+	Nick           string   `json:"nick"`
+	OppShots       []string `json:"opp_shots"`
+	Opponent       string   `json:"opponent"`
+	ShouldFire     bool     `json:"should_fire"`
+	Timer          int      `json:"timer"`
+}
+
+// Validate checks if status received from server is usable.
+func (s GameStatus) Validate() error {
+	switch s.GameStatus {
+	case StatusNoGame, StatusWaiting, StatusWaitingWPBot, StatusInProgress, StatusEnded:
+	default:
+		return fmt.Errorf("game status: unknown game_status %q", s.GameStatus)
+	}
+	for _, coord := range s.OppShots {
+		if err := validateCoord(coord); err != nil {
+			return fmt.Errorf("game status: %w", err)
+		}
+	}
+	return nil
+}
+
+// BoardResponse is a response with the locations of the player's ships.
+type BoardResponse struct {
+	Board []string `json:"board"`
+}
+
+// Validate checks if board received from server is usable.
+func (b BoardResponse) Validate() error {
+	if len(b.Board) == 0 {
+		return errors.New("board: no ships on the board")
+	}
+	for _, coord := range b.Board {
+		if err := validateCoord(coord); err != nil {
+			return fmt.Errorf("board: %w", err)
+		}
+	}
+	return nil
+}
+
+// FireResult is a response after firing at the opponent's board.
+type FireResult struct {
+	Result string `json:"result"`
+}
+
+// Validate checks if result received from server is usable.
+func (f FireResult) Validate() error {
+	switch f.Result {
+	case ResultHit, ResultMiss, ResultSunk:
+		return nil
+	}
+	return fmt.Errorf("fire result: unknown result %q", f.Result)
+}
+
+// IsHit checks if the shot has hit (or sunk) the ship.
+func (f FireResult) IsHit() bool {
+	return f.Result == ResultHit || f.Result == ResultSunk
+}
+
+// DescResponse is a response with nicks and descriptions of both players.
+type DescResponse struct {
+	Desc     string `json:"desc"`
+	Nick     string `json:"nick"`
+	OppDesc  string `json:"opp_desc"`
+	Opponent string `json:"opponent"`
+}
+
+// Validate checks if descriptions received from server are usable.
+func (d DescResponse) Validate() error {
+	if d.Nick == "" {
+		return errors.New("descriptions: nick of the player is empty")
+	}
+	return nil
+}
+
+// LobbyPlayer is a single player waiting in the lobby.
+type LobbyPlayer struct {
+	GameStatus string `json:"game_status"`
+	Nick       string `json:"nick"`
+}
+
+// LobbyResponse is a list of players waiting in the lobby.
+type LobbyResponse []LobbyPlayer
+
+// Validate checks if lobby received from server is usable.
+func (l LobbyResponse) Validate() error {
+	for _, player := range l {
+		if player.Nick == "" {
+			return errors.New("lobby: player without nick")
+		}
+	}
+	return nil
+}
+
+// PlayerStats are statistics of a single player.
+type PlayerStats struct {
+	Games  int    `json:"games"`
+	Nick   string `json:"nick"`
+	Points int    `json:"points"`
+	Rank   int    `json:"rank"`
+	Wins   int    `json:"wins"`
+}
+
+// StatsResponse is a response with statistics of the top players.
+type StatsResponse struct {
+	Stats []PlayerStats `json:"stats"`
+}
+
+// Validate checks if statistics received from server are usable.
+func (s StatsResponse) Validate() error {
+	for _, player := range s.Stats {
+		if player.Nick == "" {
+			return errors.New("stats: player without nick")
+		}
+	}
+	return nil
+}
+
+// PlayerStatsResponse is a response with statistics of the specific player.
+type PlayerStatsResponse struct {
+	Stats PlayerStats `json:"stats"`
+}
+
+// Validate checks if statistics received from server are usable.
+func (s PlayerStatsResponse) Validate() error {
+	if s.Stats.Nick == "" {
+		return errors.New("player stats: player without nick")
+	}
+	return nil
+}
+
+// ErrorResponse is a body that server sends together with unsuccessful status code.
+type ErrorResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// ----- HELPERS -----------------------------------------------------------------------
+
+// validateCoord checks if coordinate is inside of the 10x10 board.
+//
+//	Arguments:
+//
+// coord - Coordinate to check (eg. "B10").
+//
+//	Returns:
+//
+// error - If coordinate is invalid, returns error with explanation.
+func validateCoord(coord string) error {
+	x, y, err := util.CoordToIntegers(coord)
+	if err != nil {
+		return fmt.Errorf("invalid coordinate %q: %w", coord, err)
+	}
+	if x < 1 || x > 10 || y < 1 || y > 10 {
+		return fmt.Errorf("coordinate %q is outside of the board", coord)
+	}
+	return nil
+}
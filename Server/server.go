@@ -0,0 +1,603 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	mathrand "math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	engine "sea-of-pirates/Engine"
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+)
+
+// ----- GLOBAL  ----------------------------------------------------------------------
+
+// APIPrefix is the path under which the whole API is served. Client should use
+// server URL with this prefix (eg. "http://localhost:8080/api/").
+const APIPrefix = "/api/"
+
+// TurnTimeout is the time that player has for a single move.
+const TurnTimeout = 60 * time.Second
+
+// LobbyTimeout is the time after which waiting player without refresh leaves the lobby.
+const LobbyTimeout = 60 * time.Second
+
+// Server is an in-process stand-in of the game server. It implements the same REST
+// API (game, board, fire, abandon, desc, refresh, lobby and stats) with a built-in bot.
+//
+// mutex - Guards all the state of the server.
+//
+// sessions - Players by their authorization token.
+//
+// stats - Statistics of players by their nick.
+//
+// random - Source of randomness for bots and the first turn.
+//
+// now - Clock of the server (replaceable to control timers).
+type Server struct {
+	mutex    sync.Mutex
+	mux      *http.ServeMux
+	sessions map[string]*player
+	stats    map[string]*models.PlayerStats
+	random   *mathrand.Rand
+	now      func() time.Time
+}
+
+// player is a single session of the player.
+//
+// lastSeen - Time of the last request of the player (used by the lobby).
+//
+// game - Game that player is taking part in (nil while waiting).
+//
+// lastGameStatus - Result of the last finished game (win / lose).
+type player struct {
+	token          string
+	nick           string
+	desc           string
+	targetNick     string
+	wpbot          bool
+	coords         []string
+	lastSeen       time.Time
+	game           *game
+	lastGameStatus string
+}
+
+// game is a single match between two players (second one might be a bot).
+//
+// players - Both sides of the game.
+//
+// engine - Engine that adjudicates the shots (side of the player is its index in players).
+//
+// bot - Built-in bot playing as players[1] (nil for two humans).
+//
+// turnStarted - Time when the current turn began.
+type game struct {
+	players     [2]*player
+	engine      *engine.Game
+	bot         *engine.Bot
+	turnStarted time.Time
+}
+
+// NewServer creates the local server with empty lobby and statistics.
+//
+//	Returns:
+//
+// *Server - Server that can be used as http.Handler.
+func NewServer() *Server {
+	s := &Server{
+		mux:      http.NewServeMux(),
+		sessions: map[string]*player{},
+		stats:    map[string]*models.PlayerStats{},
+		random:   mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+		now:      time.Now,
+	}
+
+	s.mux.HandleFunc("POST "+APIPrefix+"game", s.handleStartGame)
+	s.mux.HandleFunc("GET "+APIPrefix+"game", s.withPlayer(s.handleStatus))
+	s.mux.HandleFunc("GET "+APIPrefix+"game/board", s.withPlayer(s.handleBoard))
+	s.mux.HandleFunc("POST "+APIPrefix+"game/fire", s.withPlayer(s.handleFire))
+	s.mux.HandleFunc("DELETE "+APIPrefix+"game/abandon", s.withPlayer(s.handleAbandon))
+	s.mux.HandleFunc("GET "+APIPrefix+"game/desc", s.withPlayer(s.handleDesc))
+	s.mux.HandleFunc("GET "+APIPrefix+"game/refresh", s.withPlayer(s.handleRefresh))
+	s.mux.HandleFunc("GET "+APIPrefix+"lobby", s.handleLobby)
+	s.mux.HandleFunc("GET "+APIPrefix+"stats", s.handleStats)
+	s.mux.HandleFunc("GET "+APIPrefix+"stats/{nick}", s.handleStatsOfPlayer)
+
+	return s
+}
+
+// NewTestServer starts the local server on a random local port (for tests).
+// Client should use srv.URL + APIPrefix as server URL and close srv at the end.
+//
+//	Returns:
+//
+// *httptest.Server - Running server.
+func NewTestServer() *httptest.Server {
+	return httptest.NewServer(NewServer())
+}
+
+// SetSeed makes bots and the choice of the first turn reproducible.
+//
+//	Arguments:
+//
+// seed - Seed for the source of randomness.
+func (s *Server) SetSeed(seed int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.random = mathrand.New(mathrand.NewSource(seed))
+}
+
+// ServeHTTP makes Server an http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ----- HANDLERS ---------------------------------------------------------------------
+
+// handleStartGame creates new session of the player and either starts a game or
+// puts the player into the lobby.
+func (s *Server) handleStartGame(w http.ResponseWriter, r *http.Request) {
+	var request models.StartGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "can't decode request: "+err.Error())
+		return
+	}
+	if request.Nick == "" {
+		writeError(w, http.StatusBadRequest, "nick can't be empty")
+		return
+	}
+	if err := request.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := engine.NewFleet(request.Coords); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//Fleet of the bot is generated before the server gets locked
+	var botCoords []string
+	if request.WPBot {
+		botCoords = s.botFleet()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.nickTaken(request.Nick) {
+		writeError(w, http.StatusConflict, "nick "+request.Nick+" is already waiting or playing")
+		return
+	}
+
+	p := &player{
+		token:      newToken(),
+		nick:       request.Nick,
+		desc:       request.Desc,
+		targetNick: request.TargetNick,
+		wpbot:      request.WPBot,
+		coords:     request.Coords,
+		lastSeen:   s.now(),
+	}
+	s.sessions[p.token] = p
+
+	if p.wpbot {
+		s.startWithBot(p, botCoords)
+	} else if opponent := s.findOpponent(p); opponent != nil {
+		s.startGame(opponent, p)
+	}
+
+	w.Header().Set("X-Auth-Token", p.token)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleStatus answers with the status of the game of the player.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request, p *player) {
+	status := models.GameStatus{
+		GameStatus:     models.StatusWaiting,
+		LastGameStatus: p.lastGameStatus,
+		Nick:           p.nick,
+		OppShots:       []string{},
+	}
+
+	if g := p.game; g != nil {
+		me := g.index(p)
+		status.Opponent = g.players[1-me].nick
+		status.OppShots = append(status.OppShots, g.engine.Shots(1-me)...)
+
+		if g.engine.Ended() {
+			status.GameStatus = models.StatusEnded
+		} else {
+			status.GameStatus = models.StatusInProgress
+			status.ShouldFire = g.engine.Turn() == me
+			status.Timer = int((TurnTimeout - s.now().Sub(g.turnStarted)).Seconds())
+		}
+	}
+
+	writeJSON(w, status)
+}
+
+// handleBoard answers with the ships of the player.
+func (s *Server) handleBoard(w http.ResponseWriter, r *http.Request, p *player) {
+	writeJSON(w, models.BoardResponse{Board: p.coords})
+}
+
+// handleFire adjudicates the shot of the player. Hit or sunk gives another turn.
+func (s *Server) handleFire(w http.ResponseWriter, r *http.Request, p *player) {
+	var request models.FireRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "can't decode request: "+err.Error())
+		return
+	}
+
+	g := p.game
+	if g == nil {
+		writeError(w, http.StatusBadRequest, engine.ErrGameEnded.Error())
+		return
+	}
+
+	result, err := s.fire(g, g.index(p), request.Coord)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Bot moves right after the turn has passed to it
+	if g.bot != nil {
+		s.botTurn(g)
+	}
+
+	writeJSON(w, models.FireResult{Result: result})
+}
+
+// handleAbandon ends the game of the player with a forfeit or takes the waiting
+// player out of the lobby. Session of the finished game is kept, so the player can
+// still read last_game_status (it is dropped by the next game with the same nick).
+func (s *Server) handleAbandon(w http.ResponseWriter, r *http.Request, p *player) {
+	switch {
+	case p.game == nil:
+		delete(s.sessions, p.token)
+	case !p.game.engine.Ended():
+		p.game.engine.Forfeit(p.game.index(p))
+		s.finish(p.game)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleDesc answers with nicks and descriptions of both players.
+func (s *Server) handleDesc(w http.ResponseWriter, r *http.Request, p *player) {
+	desc := models.DescResponse{Desc: p.desc, Nick: p.nick}
+	if g := p.game; g != nil {
+		opponent := g.players[1-g.index(p)]
+		desc.Opponent = opponent.nick
+		desc.OppDesc = opponent.desc
+	}
+
+	writeJSON(w, desc)
+}
+
+// handleRefresh keeps the session of the player alive (lastSeen is updated by withPlayer).
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request, p *player) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleLobby answers with the players waiting for a game.
+func (s *Server) handleLobby(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lobby := models.LobbyResponse{}
+	for _, p := range s.waitingPlayers() {
+		lobby = append(lobby, models.LobbyPlayer{GameStatus: models.StatusWaiting, Nick: p.nick})
+	}
+
+	writeJSON(w, lobby)
+}
+
+// handleStats answers with statistics of the top 10 players.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ranking := s.ranking()
+	if len(ranking) > 10 {
+		ranking = ranking[:10]
+	}
+
+	writeJSON(w, models.StatsResponse{Stats: ranking})
+}
+
+// handleStatsOfPlayer answers with statistics of the specific player.
+func (s *Server) handleStatsOfPlayer(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	nick := r.PathValue("nick")
+	for _, stats := range s.ranking() {
+		if stats.Nick == nick {
+			writeJSON(w, models.PlayerStatsResponse{Stats: stats})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "no statistics of player "+nick)
+}
+
+// withPlayer authorizes the request by X-Auth-Token and passes the player to handler.
+// It also locks the server and checks timers before the handler is called.
+//
+//	Arguments:
+//
+// handler - Handler that needs the player of the request.
+//
+//	Returns:
+//
+// http.HandlerFunc - Handler that can be registered in the mux.
+func (s *Server) withPlayer(handler func(http.ResponseWriter, *http.Request, *player)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		p, ok := s.sessions[r.Header.Get("X-Auth-Token")]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid or missing authorization token")
+			return
+		}
+
+		p.lastSeen = s.now()
+		if p.game != nil {
+			s.checkTimer(p.game)
+		}
+
+		handler(w, r, p)
+	}
+}
+
+// ----- GAME    ----------------------------------------------------------------------
+
+// botFleet generates the fleet of the built-in bot. Server is locked only to get
+// the seed, generating the fleet does not block other requests.
+func (s *Server) botFleet() []string {
+	s.mutex.Lock()
+	seed := s.random.Int63()
+	s.mutex.Unlock()
+
+	coords, err := util.RandomFleet(util.FleetOptions{Seed: seed})
+	if err != nil {
+		return engine.BotFleet
+	}
+	return coords
+}
+
+// startWithBot starts the game of the player against the built-in bot with the given fleet.
+func (s *Server) startWithBot(p *player, coords []string) {
+	botPlayer := &player{nick: engine.BotNick, desc: engine.BotDesc, coords: coords}
+	s.startGame(p, botPlayer)
+	p.game.bot = engine.NewBot(s.random)
+	s.botTurn(p.game)
+}
+
+// startGame starts the game between two players, the first turn is random.
+func (s *Server) startGame(first *player, second *player) {
+	// Fleets were validated when players joined
+	match, _ := engine.NewGame(first.coords, second.coords, s.random.Intn(2))
+	g := &game{
+		players:     [2]*player{first, second},
+		engine:      match,
+		turnStarted: s.now(),
+	}
+	first.game = g
+	second.game = g
+}
+
+// fire passes the shot of the player to the engine and restarts the turn timer.
+//
+//	Returns:
+//
+// string - One of models.Result* constants.
+//
+// error - If the shot is not allowed.
+func (s *Server) fire(g *game, shooter int, coord string) (string, error) {
+	result, err := g.engine.Fire(shooter, coord)
+	if err != nil {
+		return "", err
+	}
+
+	if g.engine.Ended() {
+		s.finish(g)
+	}
+	g.turnStarted = s.now()
+	return result, nil
+}
+
+// botTurn lets the bot fire until it misses or wins.
+func (s *Server) botTurn(g *game) {
+	for !g.engine.Ended() && g.engine.Turn() == 1 {
+		shot := g.bot.NextShot()
+		result, _ := s.fire(g, 1, shot.Coord())
+		g.bot.Learn(shot, result != models.ResultMiss)
+	}
+}
+
+// checkTimer ends the game with a forfeit when the player ran out of time.
+func (s *Server) checkTimer(g *game) {
+	if !g.engine.Ended() && s.now().Sub(g.turnStarted) > TurnTimeout {
+		g.engine.Forfeit(g.engine.Turn())
+		s.finish(g)
+	}
+}
+
+// finish updates statistics of both players after the game is over.
+//
+//	Arguments:
+//
+// g - Game that is over.
+func (s *Server) finish(g *game) {
+	for i, p := range g.players {
+		stats := s.statsOf(p.nick)
+		stats.Games++
+		if i == g.engine.Winner() {
+			p.lastGameStatus = models.LastWin
+			stats.Wins++
+			stats.Points += 3
+		} else {
+			p.lastGameStatus = models.LastLose
+		}
+	}
+}
+
+// index returns the index of the player inside of the game.
+func (g *game) index(p *player) int {
+	if g.players[0] == p {
+		return 0
+	}
+	return 1
+}
+
+// ----- LOBBY   ----------------------------------------------------------------------
+
+// waitingPlayers returns players in the lobby, dropping ones that did not refresh in time.
+func (s *Server) waitingPlayers() []*player {
+	waiting := []*player{}
+	for token, p := range s.sessions {
+		if p.game != nil {
+			continue
+		}
+		if s.now().Sub(p.lastSeen) > LobbyTimeout {
+			delete(s.sessions, token)
+			continue
+		}
+		waiting = append(waiting, p)
+	}
+
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].nick < waiting[j].nick })
+	return waiting
+}
+
+// findOpponent looks for the waiting player that can play against the given one.
+// Player chosen by target nick has priority over the one that targets given player.
+func (s *Server) findOpponent(p *player) *player {
+	for _, waiting := range s.waitingPlayers() {
+		if waiting == p {
+			continue
+		}
+		if p.targetNick != "" && waiting.nick == p.targetNick && (waiting.targetNick == "" || waiting.targetNick == p.nick) {
+			return waiting
+		}
+		if p.targetNick == "" && waiting.targetNick == p.nick {
+			return waiting
+		}
+	}
+	return nil
+}
+
+// nickTaken checks if player with the nick is already waiting or playing.
+func (s *Server) nickTaken(nick string) bool {
+	for token, p := range s.sessions {
+		if p.nick != nick {
+			continue
+		}
+		// Finished games do not block the nick
+		if p.game != nil && p.game.engine.Ended() {
+			delete(s.sessions, token)
+			continue
+		}
+		if p.game == nil && s.now().Sub(p.lastSeen) > LobbyTimeout {
+			delete(s.sessions, token)
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// ----- STATS   ----------------------------------------------------------------------
+
+// statsOf returns statistics of the player, creating them if needed.
+func (s *Server) statsOf(nick string) *models.PlayerStats {
+	stats, ok := s.stats[nick]
+	if !ok {
+		stats = &models.PlayerStats{Nick: nick}
+		s.stats[nick] = stats
+	}
+	return stats
+}
+
+// ranking returns statistics of all players sorted by points with ranks filled in.
+func (s *Server) ranking() []models.PlayerStats {
+	ranking := []models.PlayerStats{}
+	for _, stats := range s.stats {
+		ranking = append(ranking, *stats)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Points != ranking[j].Points {
+			return ranking[i].Points > ranking[j].Points
+		}
+		return ranking[i].Nick < ranking[j].Nick
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
+
+// ----- RUNNING ----------------------------------------------------------------------
+
+// ListenAndServe runs the local server on the given address until ctx is cancelled.
+//
+//	Arguments:
+//
+// ctx - Context that stops the server.
+//
+// addr - Address to listen on (eg. ":8080").
+//
+//	Returns:
+//
+// error - Error that stopped the server (nil after cancellation).
+func ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: NewServer()}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// newToken generates random authorization token.
+func newToken() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(errors.New("can't generate authorization token: " + err.Error()))
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// writeJSON sends the value as JSON body with status 200.
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError sends the error message as JSON body with given status code.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(models.ErrorResponse{Message: message})
+}
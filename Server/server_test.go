@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	engine "sea-of-pirates/Engine"
+	models "sea-of-pirates/Models"
+)
+
+// call sends the request to the test server and decodes the JSON answer (if out is not nil).
+func call(t *testing.T, url string, method string, path string, token string, body any, out any) *http.Response {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("encode %s %s: %v", method, path, err)
+		}
+	}
+	request, err := http.NewRequest(method, url+APIPrefix+path, &payload)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if token != "" {
+		request.Header.Set("X-Auth-Token", token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer response.Body.Close()
+
+	if out != nil && response.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, path, err)
+		}
+	}
+	return response
+}
+
+func TestGameWithBot(t *testing.T) {
+	srv := NewTestServer()
+	defer srv.Close()
+
+	//Starting the game against the bot
+	request := models.StartGameRequest{Nick: "tester", Desc: "test", Coords: engine.BotFleet, WPBot: true}
+	response := call(t, srv.URL, http.MethodPost, "game", "", request, nil)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("start game: status %d", response.StatusCode)
+	}
+	token := response.Header.Get("X-Auth-Token")
+	if token == "" {
+		t.Fatal("start game: no X-Auth-Token")
+	}
+
+	//Bot has already fired if it started, so it is the player's turn
+	var status models.GameStatus
+	call(t, srv.URL, http.MethodGet, "game", token, nil, &status)
+	if status.GameStatus != models.StatusInProgress || !status.ShouldFire || status.Opponent != engine.BotNick {
+		t.Fatalf("status after start = %+v, want player's turn against %s", status, engine.BotNick)
+	}
+
+	//Firing
+	var fired models.FireResult
+	response = call(t, srv.URL, http.MethodPost, "game/fire", token, models.FireRequest{Coord: "A1"}, &fired)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("fire: status %d", response.StatusCode)
+	}
+	switch fired.Result {
+	case models.ResultMiss, models.ResultHit, models.ResultSunk:
+	default:
+		t.Fatalf("fire: unknown result %q", fired.Result)
+	}
+	if fired.Result != models.ResultMiss {
+		response = call(t, srv.URL, http.MethodPost, "game/fire", token, models.FireRequest{Coord: "A1"}, nil)
+		if response.StatusCode != http.StatusBadRequest {
+			t.Fatalf("fire at the same field: status %d, want %d", response.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	//Abandoning, the result is still available
+	response = call(t, srv.URL, http.MethodDelete, "game/abandon", token, nil, nil)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("abandon: status %d", response.StatusCode)
+	}
+	status = models.GameStatus{}
+	response = call(t, srv.URL, http.MethodGet, "game", token, nil, &status)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("status after abandon: status %d", response.StatusCode)
+	}
+	if status.GameStatus != models.StatusEnded || status.LastGameStatus != models.LastLose {
+		t.Fatalf("status after abandon = %+v, want ended and lost", status)
+	}
+
+	//Nick is free again
+	response = call(t, srv.URL, http.MethodPost, "game", "", request, nil)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("start next game: status %d", response.StatusCode)
+	}
+}
+
+func TestAbandonLeavesLobby(t *testing.T) {
+	srv := NewTestServer()
+	defer srv.Close()
+
+	request := models.StartGameRequest{Nick: "waiting", Coords: engine.BotFleet}
+	response := call(t, srv.URL, http.MethodPost, "game", "", request, nil)
+	token := response.Header.Get("X-Auth-Token")
+
+	var lobby models.LobbyResponse
+	call(t, srv.URL, http.MethodGet, "lobby", "", nil, &lobby)
+	if len(lobby) != 1 || lobby[0].Nick != "waiting" {
+		t.Fatalf("lobby = %+v, want the waiting player", lobby)
+	}
+
+	call(t, srv.URL, http.MethodDelete, "game/abandon", token, nil, nil)
+	lobby = nil
+	call(t, srv.URL, http.MethodGet, "lobby", "", nil, &lobby)
+	if len(lobby) != 0 {
+		t.Fatalf("lobby after abandon = %+v, want empty", lobby)
+	}
+	if response := call(t, srv.URL, http.MethodGet, "game", token, nil, nil); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status after leaving the lobby: status %d, want %d", response.StatusCode, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,251 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	ai "sea-of-pirates/AI"
+	util "sea-of-pirates/util"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- ASSIST  ----------------------------------------------------------------------
+
+// autoFireDelay is the time between shots in the auto-fire mode, so player can follow them.
+const autoFireDelay = 700 * time.Millisecond
+
+// assistHint and assistAuto are the modes of the assistant at the start of the game
+// (changed on the settings screen).
+var assistHint, assistAuto bool
+
+// hintColor is the color of the suggested field on the enemy board.
+var hintColor = gui.NewColor(230, 200, 60)
+
+// assistant helps the player with aiming. It can highlight the best field on the
+// enemy board (hint) or fire at it automatically (auto-fire).
+//
+// targeting - Engine that ranks fields of the enemy board.
+//
+// hint - Is the hint shown.
+//
+// auto - Is the auto-fire mode on.
+//
+// info - Reason why the last clicked field was refused.
+type assistant struct {
+	targeting  *ai.Targeting
+	hint       bool
+	auto       bool
+	hintButton *Button
+	autoButton *Button
+	info       *gui.Text
+}
+
+// newAssistant creates the assistant and draws its buttons.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the buttons.
+//
+// y - Integer y coordinate of the buttons.
+//
+//	Returns:
+//
+// *assistant - Assistant with hint and auto-fire set as on the settings screen.
+func newAssistant(x int, y int) *assistant {
+	a := &assistant{
+		targeting:  ai.NewTargeting(nil),
+		hint:       assistHint,
+		auto:       assistAuto,
+		hintButton: NewButton(x, y, "", 'h'),
+		autoButton: NewButton(x+20, y, "", 'a'),
+		info:       DrawGUIText(x, y+2, "", nil),
+	}
+	a.updateLabels()
+	ui.Draw(a.hintButton)
+	ui.Draw(a.autoButton)
+	return a
+}
+
+// enemyBoardConfig returns configuration of the enemy board where Ship state
+// (never used for the opponent's fleet) shows the hint.
+func enemyBoardConfig() *gui.BoardConfig {
+	cfg := boardConfig()
+	cfg.ShipColor = hintColor
+	applyColor(&cfg.ShipColor, boardColors.Hint)
+	cfg.ShipChar = '?'
+	return cfg
+}
+
+// remove removes buttons of the assistant from the screen.
+func (a *assistant) remove() {
+	ui.Remove(a.hintButton)
+	ui.Remove(a.autoButton)
+	ui.Remove(a.info)
+}
+
+// record tells the targeting engine about the result of the player's shot.
+//
+//	Arguments:
+//
+// coord - Coordinate of the shot.
+//
+// result - One of models.Result* constants.
+func (a *assistant) record(coord string, result string) {
+	errorCheck(a.targeting.Record(coord, result))
+}
+
+// chooseShot waits until the player clicks the field of the enemy board, or picks
+// the field by itself in the auto-fire mode. Hint and auto-fire can be toggled meanwhile.
+// Fields that can't be fired at are refused (with the reason shown) and waiting goes on.
+//
+//	Arguments:
+//
+// ctx - Context that stops waiting.
+//
+// fields - Channel with clicked fields of the enemy board.
+//
+// board - Enemy board.
+//
+// countdown - Countdown of the turn (nil if the turn has no deadline), redrawn while
+// waiting. When fireOnTimeout is set, the best guess is fired timeoutFireMargin
+// before its deadline.
+//
+// forfeit - Give-up control (player can give up instead of firing).
+//
+//	Returns:
+//
+// string - Coordinate to fire at (empty if ctx was cancelled or player gave up).
+func (a *assistant) chooseShot(ctx context.Context, fields <-chan string, board *gui.Board, countdown *turnTimer, forfeit *giveUp) string {
+	//Forgetting clicks made during the opponent's turn
+	drainFields(fields)
+	defer board.SetStates(opponentStates)
+	defer a.info.SetText("")
+
+	//Firing the best guess before the turn expires
+	var timeout <-chan time.Time
+	if fireOnTimeout && countdown != nil {
+		timer := time.NewTimer(max(time.Until(countdown.deadline)-timeoutFireMargin, 0))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	suggestion := ""
+	var autoFire <-chan time.Time
+	refresh := true
+	for {
+		if refresh {
+			var err error
+			if suggestion, err = a.targeting.Suggest(); err != nil {
+				suggestion = ""
+			}
+			a.showHint(board, suggestion)
+
+			//Firing automatically after a short delay (unless auto-fire gets turned off)
+			autoFire = nil
+			if a.auto && suggestion != "" {
+				autoFire = time.After(autoFireDelay)
+			}
+		}
+		refresh = true
+
+		select {
+		case <-ctx.Done():
+			return ""
+		case <-countdown.Ticks():
+			//Only the countdown changes, auto-fire keeps its time
+			countdown.update()
+			refresh = false
+		case field, ok := <-fields:
+			if !ok {
+				return ""
+			}
+			if reason := a.refusal(field); reason != "" {
+				a.info.SetText(reason)
+				continue
+			}
+			return field
+		case <-autoFire:
+			return suggestion
+		case <-timeout:
+			if suggestion != "" {
+				return suggestion
+			}
+		case <-forfeit.Clicks():
+			if forfeit.ask(ctx) {
+				return ""
+			}
+		case <-a.hintButton.Clicks():
+			a.hint = !a.hint
+			a.updateLabels()
+		case <-a.autoButton.Clicks():
+			a.auto = !a.auto
+			a.updateLabels()
+		}
+	}
+}
+
+// refusal checks if the field can be fired at.
+//
+//	Arguments:
+//
+// coord - Coordinate of the field (eg. "B10").
+//
+//	Returns:
+//
+// string - Why the field can't be fired at (empty if it can).
+func (a *assistant) refusal(coord string) string {
+	x, y, err := util.CoordToIntegers(coord)
+	if err != nil || x < 1 || x > 10 || y < 1 || y > 10 {
+		return fmt.Sprintf("%q is not a field of the board, pick another one", coord)
+	}
+	coord = util.IntegersToCoord(x, y)
+
+	if slices.Contains(a.targeting.Excluded(), coord) {
+		return coord + " is next to the sunk ship, pick another field"
+	}
+	if opponentStates[x-1][y-1] != gui.Empty {
+		return coord + " was already shot, pick another field"
+	}
+	return ""
+}
+
+// showHint draws the enemy board with the suggested field highlighted (if hint is on).
+func (a *assistant) showHint(board *gui.Board, suggestion string) {
+	states := opponentStates
+	if a.hint && suggestion != "" {
+		if x, y, err := util.CoordToIntegers(suggestion); err == nil {
+			states[x-1][y-1] = gui.Ship
+		}
+	}
+	board.SetStates(states)
+}
+
+// updateLabels shows the current modes on the buttons.
+func (a *assistant) updateLabels() {
+	a.hintButton.SetLabel("Hint: " + onOff(a.hint) + " (h)")
+	a.autoButton.SetLabel("Auto-fire: " + onOff(a.auto) + " (a)")
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// onOff translates the flag into "on" or "off".
+func onOff(flag bool) string {
+	if flag {
+		return "on"
+	}
+	return "off"
+}
+
+// drainFields forgets all the fields that are waiting in the channel.
+func drainFields(fields <-chan string) {
+	for {
+		select {
+		case <-fields:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	engine "sea-of-pirates/Engine"
+	http "sea-of-pirates/HTTP"
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+)
+
+// ----- BACKEND ----------------------------------------------------------------------
+
+// Backend is the source of truth of the game. It is implemented both by the remote
+// game server (RemoteBackend) and by the local engine (LocalBackend), so the game
+// flow does not care which one is used.
+type Backend interface {
+	// StartGame begins the game with the given fleet and profile of the player.
+	StartGame(ctx context.Context, request models.StartGameRequest) error
+	// GameStatus returns the current status of the game.
+	GameStatus(ctx context.Context) (models.GameStatus, error)
+	// Board returns coordinates of the player's ships.
+	Board(ctx context.Context) ([]string, error)
+	// Fire shoots at the given coordinate of the opponent's board.
+	Fire(ctx context.Context, coord string) (models.FireResult, error)
+	// GiveUp abandons the current game.
+	GiveUp(ctx context.Context) error
+	// Descriptions returns nicks and descriptions of both players.
+	Descriptions(ctx context.Context) (models.DescResponse, error)
+	// RefreshSession keeps the session of the player alive.
+	RefreshSession(ctx context.Context) error
+}
+
+// ----- REMOTE  ----------------------------------------------------------------------
+
+// RemoteBackend plays the game on the game server through the HTTP client.
+type RemoteBackend struct {
+	client *http.Client
+}
+
+// NewRemoteBackend creates the backend that uses the given HTTP client.
+//
+//	Arguments:
+//
+// c - Client of the game server. If nil, http.DefaultClient() is used.
+//
+//	Returns:
+//
+// *RemoteBackend - Recently created backend.
+func NewRemoteBackend(c *http.Client) *RemoteBackend {
+	if c == nil {
+		c = http.DefaultClient()
+	}
+	return &RemoteBackend{client: c}
+}
+
+// StartGame begins the game on the server (token is kept inside of the client).
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// request - Profile and fleet of the player.
+//
+//	Returns:
+//
+// error - If server refused the game or request failed.
+func (b *RemoteBackend) StartGame(ctx context.Context, request models.StartGameRequest) error {
+	return b.client.StartGame(ctx, request).Err
+}
+
+// GameStatus retrieves the status of the game from the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.GameStatus - Status of the game.
+//
+// error - If request failed.
+func (b *RemoteBackend) GameStatus(ctx context.Context) (models.GameStatus, error) {
+	status, response := b.client.GameStatus(ctx)
+	return status, response.Err
+}
+
+// Board retrieves the player's ships from the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// []string - Coordinates of the player's ships.
+//
+// error - If request failed.
+func (b *RemoteBackend) Board(ctx context.Context) ([]string, error) {
+	board, response := b.client.GetMyGameBoard(ctx)
+	return board.Board, response.Err
+}
+
+// Fire shoots at the coordinate of the opponent's board on the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+// coord - Coordinate to fire at (eg. "B10").
+//
+//	Returns:
+//
+// models.FireResult - Result of the shot (hit / miss / sunk).
+//
+// error - If server refused the shot or request failed.
+func (b *RemoteBackend) Fire(ctx context.Context, coord string) (models.FireResult, error) {
+	result, response := b.client.Fire(ctx, coord)
+	return result, response.Err
+}
+
+// GiveUp abandons the current game on the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// error - If request failed.
+func (b *RemoteBackend) GiveUp(ctx context.Context) error {
+	return b.client.GiveUp(ctx).Err
+}
+
+// Descriptions retrieves nicks and descriptions of both players from the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.DescResponse - Nicks and descriptions of both players.
+//
+// error - If request failed.
+func (b *RemoteBackend) Descriptions(ctx context.Context) (models.DescResponse, error) {
+	desc, response := b.client.GetMyAndOpponentDesc(ctx)
+	return desc, response.Err
+}
+
+// RefreshSession keeps the session of the player alive on the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// error - If request failed.
+func (b *RemoteBackend) RefreshSession(ctx context.Context) error {
+	return b.client.RefreshSession(ctx).Err
+}
+
+// ----- LOCAL   ----------------------------------------------------------------------
+
+// LocalBackend plays the game offline against the built-in bot without any HTTP.
+// Player is side 0 of the engine, bot is side 1.
+//
+// mutex - Guards the state (keep-alive and game flow run concurrently).
+//
+// request - Profile and fleet of the player.
+//
+// game - Engine that owns both fleets.
+//
+// bot - Opponent of the player.
+//
+// random - Source of randomness for the bot and the first turn.
+type LocalBackend struct {
+	mutex   sync.Mutex
+	request models.StartGameRequest
+	game    *engine.Game
+	bot     *engine.Bot
+	random  *rand.Rand
+}
+
+// NewLocalBackend creates the offline backend.
+//
+//	Returns:
+//
+// *LocalBackend - Recently created backend without the game.
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// errNoLocalGame is returned when the game was not started yet.
+var errNoLocalGame = errors.New("game was not started")
+
+// StartGame begins the game against the bot with its random fleet. Bot fires
+// at once if it has the first turn.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+// request - Profile and fleet of the player.
+//
+//	Returns:
+//
+// error - If request or fleet is not valid.
+func (b *LocalBackend) StartGame(ctx context.Context, request models.StartGameRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	botCoords, err := util.RandomFleet(util.FleetOptions{Seed: b.random.Int63()})
+	if err != nil {
+		botCoords = engine.BotFleet
+	}
+
+	game, err := engine.NewGame(request.Coords, botCoords, b.random.Intn(2))
+	if err != nil {
+		return err
+	}
+
+	b.request = request
+	b.game = game
+	b.bot = engine.NewBot(b.random)
+	b.botTurn()
+	return nil
+}
+
+// GameStatus returns the status of the game (StatusNoGame before the start).
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+//	Returns:
+//
+// models.GameStatus - Status of the game.
+//
+// error - Always nil.
+func (b *LocalBackend) GameStatus(ctx context.Context) (models.GameStatus, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.game == nil {
+		return models.GameStatus{GameStatus: models.StatusNoGame}, nil
+	}
+
+	status := models.GameStatus{
+		GameStatus: models.StatusInProgress,
+		Nick:       b.request.Nick,
+		OppShots:   b.game.Shots(1),
+		Opponent:   engine.BotNick,
+		ShouldFire: b.game.Turn() == 0,
+	}
+	if b.game.Ended() {
+		status.GameStatus = models.StatusEnded
+		status.ShouldFire = false
+		status.LastGameStatus = models.LastLose
+		if b.game.Winner() == 0 {
+			status.LastGameStatus = models.LastWin
+		}
+	}
+	return status, nil
+}
+
+// Board returns the player's ships.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+//	Returns:
+//
+// []string - Coordinates of the player's ships.
+//
+// error - If game was not started.
+func (b *LocalBackend) Board(ctx context.Context) ([]string, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.game == nil {
+		return nil, errNoLocalGame
+	}
+	return b.game.Fleet(0).Coords(), nil
+}
+
+// Fire shoots at the coordinate of the bot's board. Bot fires right after the
+// turn has passed to it.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+// coord - Coordinate to fire at (eg. "B10").
+//
+//	Returns:
+//
+// models.FireResult - Result of the shot (hit / miss / sunk).
+//
+// error - If game was not started or the shot is not allowed.
+func (b *LocalBackend) Fire(ctx context.Context, coord string) (models.FireResult, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.game == nil {
+		return models.FireResult{}, errNoLocalGame
+	}
+
+	result, err := b.game.Fire(0, coord)
+	if err != nil {
+		return models.FireResult{}, err
+	}
+
+	b.botTurn()
+	return models.FireResult{Result: result}, nil
+}
+
+// GiveUp ends the game with the loss of the player.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+//	Returns:
+//
+// error - If game was not started.
+func (b *LocalBackend) GiveUp(ctx context.Context) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.game == nil {
+		return errNoLocalGame
+	}
+	b.game.Forfeit(0)
+	return nil
+}
+
+// Descriptions returns nicks and descriptions of the player and the bot.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+//	Returns:
+//
+// models.DescResponse - Nicks and descriptions of both players.
+//
+// error - Always nil.
+func (b *LocalBackend) Descriptions(ctx context.Context) (models.DescResponse, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return models.DescResponse{
+		Desc:     b.request.Desc,
+		Nick:     b.request.Nick,
+		OppDesc:  engine.BotDesc,
+		Opponent: engine.BotNick,
+	}, nil
+}
+
+// RefreshSession does nothing, there is no session offline.
+//
+//	Arguments:
+//
+// ctx - Context of the call (not used, engine answers immediately).
+//
+//	Returns:
+//
+// error - Always nil.
+func (b *LocalBackend) RefreshSession(ctx context.Context) error {
+	// There is no session to keep alive offline
+	return nil
+}
+
+// botTurn lets the bot fire until it misses or wins. Mutex must be held.
+func (b *LocalBackend) botTurn() {
+	for !b.game.Ended() && b.game.Turn() == 1 {
+		shot := b.bot.NextShot()
+		result, _ := b.game.Fire(1, shot.Coord())
+		b.bot.Learn(shot, result != models.ResultMiss)
+	}
+}
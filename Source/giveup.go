@@ -0,0 +1,110 @@
+package source
+
+import (
+	"context"
+)
+
+// ----- GIVE UP ----------------------------------------------------------------------
+
+// giveUp lets the player abandon the running game with the button or the 'g' key.
+// Every request to give up has to be confirmed. Clicks are handled by the game
+// loop, so the question is drawn and the game is abandoned on its goroutine.
+//
+// button - Button that asks for giving up.
+//
+// x, y - Coordinates of the confirmation.
+//
+// forfeited - Set when the game was abandoned on the server.
+type giveUp struct {
+	button    *Button
+	x         int
+	y         int
+	forfeited bool
+}
+
+// newGiveUp draws the button.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the button.
+//
+// y - Integer y coordinate of the button (confirmation is shown two lines below).
+//
+//	Returns:
+//
+// *giveUp - Give-up control waiting for the clicks.
+func newGiveUp(x int, y int) *giveUp {
+	g := &giveUp{button: NewButton(x, y, "Give up (g)", 'g'), x: x, y: y + 2}
+	ui.Draw(g.button)
+	return g
+}
+
+// Clicks returns the channel that receives a value whenever the button is pressed.
+func (g *giveUp) Clicks() <-chan struct{} {
+	return g.button.Clicks()
+}
+
+// Forfeited checks if the player has given up the game.
+func (g *giveUp) Forfeited() bool {
+	return g.forfeited
+}
+
+// ask confirms the request to give up and abandons the game on the server.
+//
+//	Arguments:
+//
+// ctx - Context of the game.
+//
+//	Returns:
+//
+// bool - True if the game was abandoned.
+func (g *giveUp) ask(ctx context.Context) bool {
+	if !confirm(ctx, g.x, g.y, "Give up the game?") {
+		return false
+	}
+	g.forfeited = GiveUpGame(ctx)
+	return g.forfeited
+}
+
+// remove removes the button from the screen.
+func (g *giveUp) remove() {
+	ui.Remove(g.button)
+}
+
+// confirm asks the player a yes/no question.
+//
+//	Arguments:
+//
+// ctx - Context that cancels the question.
+//
+// x - Integer x coordinate of the question.
+//
+// y - Integer y coordinate of the question.
+//
+// question - Text of the question.
+//
+//	Returns:
+//
+// bool - True if the player answered yes.
+func confirm(ctx context.Context, x int, y int, question string) bool {
+	text := DrawGUIText(x, y, question, nil)
+	yesButton := NewButton(x+len(question)+1, y, "Yes (y)", 'y')
+	noButton := NewButton(x+len(question)+13, y, "No (n)", 'n')
+	ui.Draw(yesButton)
+	ui.Draw(noButton)
+
+	defer func() {
+		ui.Remove(text)
+		ui.Remove(yesButton)
+		ui.Remove(noButton)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-yesButton.Clicks():
+		return true
+	case <-noButton.Clicks():
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- KEEP ALIVE -------------------------------------------------------------------
+
+// KeepAliveInterval is the time between two refreshes of the session.
+const KeepAliveInterval = 10 * time.Second
+
+// keepAliveNoticeTime is the time the failure of refreshing stays on the screen.
+const keepAliveNoticeTime = 5 * time.Second
+
+// KeepAlive periodically refreshes the session of the player in the background,
+// so the server will not drop it during long waits for the opponent.
+//
+// idle - Refreshes are sent only while the player is idle.
+//
+// cancel - Function that stops the background refreshing.
+//
+// done - Channel closed when background refreshing has stopped.
+//
+// failure - The last failed refresh that was not reported yet.
+//
+// notice - Reported failure on the screen (nil if there is none).
+//
+// noticeUntil - Time when the notice is removed.
+type KeepAlive struct {
+	idle        atomic.Bool
+	cancel      context.CancelFunc
+	done        chan struct{}
+	failure     atomic.Pointer[error]
+	notice      *gui.Text
+	noticeUntil time.Time
+}
+
+// StartKeepAlive starts refreshing the session in the background.
+//
+//	Arguments:
+//
+// ctx - Context that stops refreshing when cancelled (eg. on game end).
+//
+// interval - Time between two refreshes.
+//
+//	Returns:
+//
+// *KeepAlive - Pointer at running keep-alive (player is marked as idle at start).
+func StartKeepAlive(ctx context.Context, interval time.Duration) *KeepAlive {
+	ctx, cancel := context.WithCancel(ctx)
+	keepAlive := &KeepAlive{cancel: cancel, done: make(chan struct{})}
+	keepAlive.idle.Store(true)
+
+	go keepAlive.run(ctx, interval)
+	return keepAlive
+}
+
+// SetIdle marks whether the player is idle (waiting in lobby or for the opponent).
+//
+//	Arguments:
+//
+// idle - True if session should be refreshed.
+func (k *KeepAlive) SetIdle(idle bool) {
+	k.idle.Store(idle)
+}
+
+// Err returns the last failure of refreshing (only once, so it is reported once).
+// Background loop does not draw, so the failure is shown by the caller (see Report).
+//
+//	Returns:
+//
+// error - Failure of the refresh (nil if there was none since the last call).
+func (k *KeepAlive) Err() error {
+	if err := k.failure.Swap(nil); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Report shows the last failure of refreshing without blocking, so waiting and the
+// turn go on. The failure stays on the screen for keepAliveNoticeTime and is removed
+// by one of the next calls, so it has to be called regularly by the waiting loop.
+func (k *KeepAlive) Report() {
+	err := k.Err()
+	if err == nil || errors.Is(err, context.Canceled) {
+		if k.notice != nil && time.Now().After(k.noticeUntil) {
+			k.removeNotice()
+		}
+		return
+	}
+
+	if k.notice == nil {
+		k.notice = DrawGUIText(50, 0, err.Error(), errorTextConfig())
+	} else {
+		k.notice.SetText(err.Error())
+	}
+	k.noticeUntil = time.Now().Add(keepAliveNoticeTime)
+}
+
+// Stop stops refreshing, waits until background work is finished and removes
+// the reported failure from the screen.
+func (k *KeepAlive) Stop() {
+	k.cancel()
+	<-k.done
+	k.removeNotice()
+}
+
+// removeNotice removes the reported failure from the screen.
+func (k *KeepAlive) removeNotice() {
+	if k.notice != nil {
+		ui.Remove(k.notice)
+		k.notice = nil
+	}
+}
+
+// run is the background loop of keep-alive. Failures are stored for Err.
+//
+//	Arguments:
+//
+// ctx - Context that stops the loop.
+//
+// interval - Time between two refreshes.
+func (k *KeepAlive) run(ctx context.Context, interval time.Duration) {
+	defer close(k.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !k.idle.Load() {
+				continue
+			}
+			if err := backend.RefreshSession(ctx); err != nil {
+				k.failure.Store(&err)
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	models "sea-of-pirates/Models"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- LOBBY   ----------------------------------------------------------------------
+
+// LobbyRefreshInterval is the time between two refreshes of the lobby.
+const LobbyRefreshInterval = 3 * time.Second
+
+// lobbyRows is the number of players shown in the lobby (they can be picked with keys 1-9).
+const lobbyRows = 9
+
+// lobbyView is the list of the waiting players shared by the lobby screens.
+//
+// title - Title of the screen.
+//
+// info - Number of the waiting players or the problem with the lobby.
+//
+// list - Waiting players.
+//
+// players - Players shown in the list (without the player).
+type lobbyView struct {
+	title   *gui.Text
+	info    *gui.Text
+	list    *List
+	players models.LobbyResponse
+}
+
+// newLobbyView draws the lobby with the given title.
+func newLobbyView(title string) *lobbyView {
+	view := &lobbyView{
+		title: DrawGUIText(1, 1, title, nil),
+		info:  DrawGUIText(1, 3, "Loading the lobby...", nil),
+		list:  NewList(1, 5, 60, lobbyRows),
+	}
+	ui.Draw(view.list)
+	return view
+}
+
+// refresh downloads the lobby and shows the waiting players. Problems are shown
+// in the view instead of the error message, so refreshing never blocks.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request.
+//
+// nick - Nick of the player (skipped on the list).
+func (v *lobbyView) refresh(ctx context.Context, nick string) {
+	lobby, response := client.Lobby(ctx)
+	if response.Err != nil {
+		v.info.SetText("Lobby is not available: " + response.Err.Error())
+		return
+	}
+
+	v.players = models.LobbyResponse{}
+	items := []string{}
+	for _, player := range lobby {
+		if player.Nick == nick {
+			continue
+		}
+		v.players = append(v.players, player)
+		items = append(items, fmt.Sprintf("%-30s %s", player.Nick, player.GameStatus))
+	}
+	v.list.SetItems(items)
+
+	v.info.SetText(fmt.Sprintf("%d player(s) waiting, refreshed at %s", len(v.players), time.Now().Format("15:04:05")))
+}
+
+// remove removes the view from the screen.
+func (v *lobbyView) remove() {
+	ui.Remove(v.title)
+	ui.Remove(v.info)
+	ui.Remove(v.list)
+}
+
+// ChooseOpponent shows the lobby where the player picks the waiting player to
+// challenge, decides to wait to be challenged or to play with the WP bot.
+// The list is refreshed every LobbyRefreshInterval.
+//
+//	Arguments:
+//
+// ctx - Context that stops the lobby.
+//
+// request - Profile of the player.
+//
+//	Returns:
+//
+// models.StartGameRequest - Request with target_nick and wpbot set by the choice.
+//
+// bool - False if lobby was interrupted or player went back.
+func ChooseOpponent(ctx context.Context, request models.StartGameRequest) (models.StartGameRequest, bool) {
+	view := newLobbyView("Lobby: pick the player to challenge (click or 1-9), wait to be challenged or play with the WP bot")
+	waitButton := NewButton(1, 6+lobbyRows, "Wait for challenge (w)", 'w')
+	botButton := NewButton(30, 6+lobbyRows, "Play with WP bot (b)", 'b')
+	refreshButton := NewButton(57, 6+lobbyRows, "Refresh (r)", 'r')
+	backButton := NewButton(75, 6+lobbyRows, "Back (q)", 'q')
+	buttons := []*Button{waitButton, botButton, refreshButton, backButton}
+	for _, button := range buttons {
+		ui.Draw(button)
+	}
+
+	defer func() {
+		view.remove()
+		for _, button := range buttons {
+			ui.Remove(button)
+		}
+	}()
+
+	ticker := time.NewTicker(LobbyRefreshInterval)
+	defer ticker.Stop()
+
+	view.refresh(ctx, request.Nick)
+	for {
+		select {
+		case <-ctx.Done():
+			return request, false
+
+		case row := <-view.list.Picks():
+			if row >= len(view.players) {
+				continue
+			}
+			request.TargetNick = view.players[row].Nick
+			request.WPBot = false
+			return request, true
+
+		case <-waitButton.Clicks():
+			request.TargetNick = ""
+			request.WPBot = false
+			return request, true
+
+		case <-botButton.Clicks():
+			request.TargetNick = ""
+			request.WPBot = true
+			return request, true
+
+		case <-backButton.Clicks():
+			return request, false
+
+		case <-refreshButton.Clicks():
+			view.refresh(ctx, request.Nick)
+
+		case <-ticker.C:
+			view.refresh(ctx, request.Nick)
+		}
+	}
+}
+
+// WaitInLobby shows the lobby while the player waits to be challenged. The lobby
+// is refreshed every LobbyRefreshInterval and the game status every poll interval.
+//
+//	Arguments:
+//
+// ctx - Context that stops waiting.
+//
+// nick - Nick of the player.
+//
+// keepAlive - Keep-alive of the session (its failures are shown while waiting).
+//
+//	Returns:
+//
+// bool - True if the game has started, false if waiting was interrupted.
+func WaitInLobby(ctx context.Context, nick string, keepAlive *KeepAlive) bool {
+	view := newLobbyView("Waiting to be challenged as " + nick + "... (other waiting players below)")
+	defer view.remove()
+
+	lobbyTicker := time.NewTicker(LobbyRefreshInterval)
+	defer lobbyTicker.Stop()
+	statusTicker := time.NewTicker(pollInterval)
+	defer statusTicker.Stop()
+
+	view.refresh(ctx, nick)
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+
+		case <-lobbyTicker.C:
+			view.refresh(ctx, nick)
+
+		case <-statusTicker.C:
+			status, ok := prepareGame(ctx)
+			if ok && status.GameStatus == models.StatusInProgress {
+				return true
+			}
+			keepAlive.Report()
+		}
+	}
+}
+
+// usesLobby checks if the player has to choose (or wait for) the opponent in the
+// lobby: only on the game server, when neither the target nor the WP bot is set.
+func usesLobby(request models.StartGameRequest) bool {
+	_, remote := backend.(*RemoteBackend)
+	return remote && request.TargetNick == "" && !request.WPBot
+}
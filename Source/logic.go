@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	http "sea-of-pirates/HTTP"
+	models "sea-of-pirates/Models"
 	"time"
 
 	util "sea-of-pirates/util"
@@ -17,6 +20,37 @@ var playerStates [10][10]gui.State
 var opponentStates [10][10]gui.State
 var errorGUIConfig *gui.TextConfig
 var ui *gui.GUI
+var client *http.Client = http.DefaultClient()
+var backend Backend
+
+// uiContext is cancelled together with the GUI (Ctrl+C or closing), so error messages
+// shown by errorCheck do not hold the program.
+var uiContext = context.Background()
+
+// pollInterval is the time between two checks of the game status.
+var pollInterval = time.Second
+
+// SetPollInterval changes the time between two checks of the game status (while
+// waiting for the opponent and during the opponent's turn).
+//
+//	Arguments:
+//
+// interval - Time between two checks (non-positive values are ignored).
+func SetPollInterval(interval time.Duration) {
+	if interval > 0 {
+		pollInterval = interval
+	}
+}
+
+// SetClient changes the client used for the game server (lobby, stats and the
+// default remote backend).
+//
+//	Arguments:
+//
+// c - Client of the game server.
+func SetClient(c *http.Client) {
+	client = c
+}
 
 // ----- GUI     ----------------------------------------------------------------------
 
@@ -41,10 +75,47 @@ func DrawGUIText(x int, y int, text string, cfg *gui.TextConfig) *gui.Text {
 	return newText
 }
 
+// DrawGUIWrappedText immediately draws text wrapped into lines of the given width.
+// If the text does not fit into maxLines, the last line ends with "...".
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the first line.
+//
+// y - Integer y coordinate of the first line.
+//
+// width - Maximal length of the line.
+//
+// maxLines - Maximal number of lines.
+//
+// text - String text to show up.
+//
+// cfg - Configuration for text labels.
+//
+//	Returns:
+//
+// []*gui.Text - Pointers at the lines for future use.
+func DrawGUIWrappedText(x int, y int, width int, maxLines int, text string, cfg *gui.TextConfig) []*gui.Text {
+	lines := util.WrapText(text, width)
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(lines[maxLines-1])
+		lines[maxLines-1] = string(last[:min(len(last), width-3)]) + "..."
+	}
+
+	texts := []*gui.Text{}
+	for i, line := range lines {
+		texts = append(texts, DrawGUIText(x, y+i, line, cfg))
+	}
+	return texts
+}
+
 // DrawGUITextFor immediately draws text on the screen for specific amount of time!
 //
 //	Arguments:
 //
+// ctx - Context that removes the text earlier when cancelled.
+//
 // x - Integer x coordinate of text.
 //
 // y - Integer y coordinate of text.
@@ -54,11 +125,9 @@ func DrawGUIText(x int, y int, text string, cfg *gui.TextConfig) *gui.Text {
 // cfg - Configuration for text label.
 //
 // time - Time in seconds for showing the text up.
-func DrawGUITextFor(x int, y int, text string, cfg *gui.TextConfig, time int) {
+func DrawGUITextFor(ctx context.Context, x int, y int, text string, cfg *gui.TextConfig, time int) {
 	timerText := DrawGUIText(x, y, text, cfg)
-	for waiting := 0; waiting < time; waiting++ {
-		WaitSecond()
-	}
+	WaitSecondsContext(ctx, time)
 	if timerText != nil {
 		ui.Remove(timerText)
 	}
@@ -137,17 +206,19 @@ func GetLogicStateChange(oldState gui.State, newState gui.State, useLogic bool)
 //
 // states - Pointer on states that are connected with previous board.
 //
-// places - Array interface with places as string (example of the inside: {"A2", "B5", "I10"}).
+// places - Array with places as string (example of the inside: {"A2", "B5", "I10"}).
 //
 // newState - The new state that should be applied for places.
 //
 // useFireLogic - Should it use the fire logic or just force to bring a new.
 // state for places.
-func FillStatesWith(board *gui.Board, states *[10][10]gui.State, places []interface{}, newState gui.State, useFireLogic bool) {
+func FillStatesWith(board *gui.Board, states *[10][10]gui.State, places []string, newState gui.State, useFireLogic bool) {
 	for _, value := range places {
 		//Retrieving information
-		first, second, err := util.CoordToIntegers(value.(string))
-		errorCheck(err)
+		first, second, err := util.CoordToIntegers(value)
+		if errorCheck(err) {
+			continue
+		}
 
 		//Placing ship in array
 		states[first-1][second-1] = GetLogicStateChange(states[first-1][second-1], newState, useFireLogic)
@@ -165,7 +236,7 @@ func FillStatesWith(board *gui.Board, states *[10][10]gui.State, places []interf
 //
 // y - Integer for the y coordinate on the screen where board should start
 //
-// cfg - Board configuration for display
+// cfg - Board configuration for display (nil means colors of the theme)
 //
 // shipPlaces - Additional argument (can be nil) that is used for setting up
 // the locations of the ships on the map.
@@ -175,8 +246,11 @@ func FillStatesWith(board *gui.Board, states *[10][10]gui.State, places []interf
 // *gui.Board - Newly created pointer on board
 //
 // [10][10]gui.State - Array of states connected with the board
-func CreateBoard(x int, y int, cfg *gui.BoardConfig, shipPlaces []interface{}) (*gui.Board, [10][10]gui.State) {
+func CreateBoard(x int, y int, cfg *gui.BoardConfig, shipPlaces []string) (*gui.Board, [10][10]gui.State) {
 	//Creating the new board
+	if cfg == nil {
+		cfg = boardConfig()
+	}
 	Board := gui.NewBoard(x, y, cfg)
 
 	//Do not forget to draw board on exit!
@@ -196,67 +270,165 @@ func CreateBoard(x int, y int, cfg *gui.BoardConfig, shipPlaces []interface{}) (
 
 // ----- GAME    ----------------------------------------------------------------------
 
+// labelWidth and labelLines limit the descriptions of the players above the boards.
+const (
+	labelWidth = 45
+	labelLines = 3
+)
+
 // BeginGame is a function that start the whole game process.
-func BeginGame() {
+//
+// Game stops when Ctrl+C is pressed (either in the GUI or as a signal).
+//
+//	Arguments:
+//
+// gameBackend - Backend that runs the game (remote server or local engine).
+// If nil, remote server of the default client is used.
+//
+// request - Profile of the player (nick, description and opponent). If coords are
+// empty, player lays out the fleet on the placement screen.
+//
+//	Returns:
+//
+// error - Why the last game could not be started (nil if it was played, the player
+// has not played at all or game was stopped by Ctrl+C).
+func BeginGame(gameBackend Backend, request models.StartGameRequest) error {
+	if gameBackend == nil {
+		gameBackend = NewRemoteBackend(client)
+	}
+	backend = gameBackend
+
+	//Context that is cancelled by Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	uiContext = ctx
+
 	//Prepare screen
 	ui = gui.NewGUI(true)
-	prepareText := DrawGUIText(1, 1, "Game is loading...", nil)
 
+	//Draw screen. Closing the GUI cancels the game as well
+	uiDone := make(chan struct{})
+	go func() {
+		ui.Start(ctx, nil)
+		cancel()
+		close(uiDone)
+	}()
+
+	//Wait for the GUI to restore the terminal on exit
+	defer func() {
+		cancel()
+		<-uiDone
+	}()
+
+	//Screens are switched until the player quits
+	return newScreens(request).run(ctx)
+}
+
+// gameOutcome is what the results screen needs to know about the finished game.
+//
+// forfeited - Player gave up the game.
+//
+// stats - Statistics of both players.
+type gameOutcome struct {
+	forfeited bool
+	stats     *gameStats
+}
+
+// playGame starts the game, waits for the opponent and plays it until the end.
+//
+//	Arguments:
+//
+// ctx - Context that stops the game.
+//
+// request - Profile of the player with the fleet and the chosen opponent.
+//
+//	Returns:
+//
+// gameOutcome - Statistics of the game and whether the player gave up.
+//
+// error - If the game could not be started or was interrupted (ctx error).
+func playGame(ctx context.Context, request models.StartGameRequest) (gameOutcome, error) {
 	//Send HTTP Request to begin the game
-	response := http.StartGame(util.JSONGetDummy())
-	errorCheck(response.Err)
-
-	//Draw screen
-	go ui.Start(context.TODO(), nil)
-
-	for {
-		status := prepareGame()
-		param, err := util.JSONGetParam(status, "game_status")
-		errorCheck(err)
-		if param == "game_in_progress" {
-			break
+	prepareText := DrawGUIText(1, 1, "Game is loading...", nil)
+	defer ui.Remove(prepareText)
+	if err := backend.StartGame(ctx, request); errorCheck(err) {
+		return gameOutcome{}, fmt.Errorf("start game: %w", err)
+	}
+
+	//Keep session alive while waiting for the opponent
+	keepAlive := StartKeepAlive(ctx, KeepAliveInterval)
+	defer keepAlive.Stop()
+
+	//Wait to be challenged in the lobby or for the chosen opponent
+	if usesLobby(request) {
+		ui.Remove(prepareText)
+		if !WaitInLobby(ctx, request.Nick, keepAlive) {
+			return gameOutcome{}, ctx.Err()
+		}
+	} else {
+		for {
+			status, ok := prepareGame(ctx)
+			if ok && status.GameStatus == models.StatusInProgress {
+				break
+			}
+			keepAlive.Report()
+			if !WaitContext(ctx, pollInterval) {
+				return gameOutcome{}, ctx.Err()
+			}
 		}
-		//util.JSONPrint(status)
-		WaitSecond()
 	}
 
 	//Clear screen and enter game flow
 	ui.Remove(prepareText)
-	enterGameFlow()
+	outcome := enterGameFlow(ctx, keepAlive)
+	return outcome, ctx.Err()
 }
 
 // prepareGame is a function that is responsible for pre-game preparations.
 //
 // It is also responsible for showing status screen and can be called many times!
 //
+// Arguments:
+//
+//	ctx - Context that stops the preparations
+//
 // Returns:
 //
-//	map[string]any - Body of HTTP request as map
-func prepareGame() map[string]any {
-	response := http.GameStatus()
-	if errorCheck(response.Err) {
-		return map[string]any{}
+//	models.GameStatus - Decoded body of HTTP request
+//
+//	bool - False if status could not be retrieved
+func prepareGame(ctx context.Context) (models.GameStatus, bool) {
+	status, err := backend.GameStatus(ctx)
+	if errorCheck(err) {
+		return status, false
 	}
 
-	info, err := util.JSONToMap(response.Body)
-	errorCheck(err)
-
-	return info
+	return status, true
 }
 
 // enterGameFlow is a function that is responsible for in-game flow.
 //
-// It waits, consumes input and is resposible for displaying the screen
-func enterGameFlow() {
+// It waits, consumes input and is resposible for displaying the screen.
+// It stops as soon as ctx is cancelled.
+//
+//	Arguments:
+//
+// ctx - Context that stops the game flow.
+//
+// keepAlive - Keep-alive that is told when the player is waiting for the opponent.
+//
+//	Returns:
+//
+// gameOutcome - Statistics of the game and whether the player gave up.
+func enterGameFlow(ctx context.Context, keepAlive *KeepAlive) gameOutcome {
+	//Giving up stops the game flow (and listening for the clicks)
+	ctx, stopGame := context.WithCancel(ctx)
+	defer stopGame()
 
 	//Battleship area setup
-	setupBoard := http.GetMyGameBoard()
-	errorCheck(setupBoard.Err)
-
-	//Retrieving board configuration
-	setupShipsDataRaw, err := util.JSONGetParamFromJSON(setupBoard.Body, "board")
+	setupShipsData, err := backend.Board(ctx)
 	errorCheck(err)
-	setupShipsData := setupShipsDataRaw.([]interface{})
 
 	//Creating Player board
 	var playerBoard *gui.Board
@@ -264,99 +436,177 @@ func enterGameFlow() {
 
 	//Creating Enemy board
 	var enemyBoard *gui.Board
-	enemyBoard, opponentStates = CreateBoard(50, 5, nil, nil)
+	enemyBoard, opponentStates = CreateBoard(50, 5, enemyBoardConfig(), nil)
+
+	//Sunk ships drawn on top of both boards
+	playerSunk := newSunkMarks(1, 5)
+	enemySunk := newSunkMarks(50, 5)
+
+	//Nicks and descriptions above the boards
+	labels := drawPlayerLabels(ctx)
+
+	//Aiming assistance and listening for the clicks on the enemy board
+	assist := newAssistant(50, 29)
+	fields := listenBoard(ctx, enemyBoard)
+	forfeit := newGiveUp(1, 29)
+
+	//Statistics of both players next to the boards
+	stats := newGameStats(setupShipsData, assist.targeting.Remaining())
+	panel := newStatsPanel(97, 5, stats)
+
+	//Hit grants another shot, so the status is not checked until the turn passes
+	extraTurn := false
+
+	//End of the turn from the timer of the last fetched status (zero if server has none).
+	//It is kept during the extra turns: server restarts the timer after every shot, so
+	//the real end can only come later and firing before the timeout stays on time.
+	//When it gets close, the status is fetched again for the real timer.
+	var deadline time.Time
 
 	//Real game flow (loop)
-	for {
+	for ctx.Err() == nil {
+		if extraTurn && (deadline.IsZero() || time.Until(deadline) > timeoutFireMargin) {
+			extraTurn = false
+		} else {
+			extraTurn = false
+
+			//Checking status (timer of the turn counts from now)
+			status, err := backend.GameStatus(ctx)
+			fetched := time.Now()
+			if errorCheck(err) {
+				WaitContext(ctx, pollInterval)
+				continue
+			}
 
-		//Checking status
-		status := http.GameStatus()
-		errorCheck(status.Err)
+			//Failures of refreshing the session in the background
+			keepAlive.Report()
 
-		//Checks for game end
-		dataMap, err := util.JSONToMap(status.Body)
-		errorCheck(err)
+			//Counting opponent's shots (also the last ones)
+			stats.recordOppShots(status.OppShots)
+			panel.update(stats)
 
-		param, err2 := util.JSONGetParam(dataMap, "game_status")
-		if errorCheck(err2) {
-			WaitSecond()
-			continue
-		}
-		if param.(string) == "ended" {
-			break
-		}
+			//Checks for game end
+			if status.GameStatus == models.StatusEnded {
+				break
+			}
 
-		//If there is no "should_fire" param, wait for your turn
-		if !util.JSONCheckParam(dataMap, "should_fire") {
-			WaitSecond()
-			continue
-		}
+			//Filling board of player with shots from opponent
+			FillStatesWith(playerBoard, &playerStates, status.OppShots, gui.Hit, true)
+			sunk, around := sunkShips(setupShipsData, status.OppShots)
+			playerSunk.update(playerBoard, &playerStates, sunk, around)
+
+			//If it is not player's turn, wait for it
+			keepAlive.SetIdle(!status.ShouldFire)
+			if !status.ShouldFire {
+				select {
+				case <-ctx.Done():
+				case <-time.After(pollInterval):
+				case <-forfeit.Clicks():
+					if forfeit.ask(ctx) {
+						stopGame()
+					}
+				}
+				continue
+			}
 
-		// Get opponents shots coordinates
-		enemyShots, assert := dataMap["opp_shots"].([]interface{})
-		if !assert {
-			errorCheck(errors.New("caution: assertion of enemyShots is not successful"))
+			deadline = time.Time{}
+			if status.Timer > 0 {
+				deadline = fetched.Add(time.Duration(status.Timer) * time.Second)
+			}
 		}
 
-		//Filling board of player with shots from opponent
-		FillStatesWith(playerBoard, &playerStates, enemyShots, gui.Hit, true)
-
-		//Showing up text indicating turn of the player
+		//Showing up text indicating turn of the player with the countdown (if server has a timer)
 		turnText := DrawGUIText(15, 0, "Your turn!", nil)
-		char := enemyBoard.Listen(context.TODO())
+		var countdown *turnTimer
+		if !deadline.IsZero() {
+			countdown = startTurnTimer(1, 0, deadline)
+		}
+		char := assist.chooseShot(ctx, fields, enemyBoard, countdown, forfeit)
 		ui.Remove(turnText)
+		if countdown != nil {
+			countdown.stop()
+		}
+		if char == "" {
+			if forfeit.Forfeited() {
+				stopGame()
+			}
+			continue
+		}
 
 		// Send Fire as HTTP request
-		response := http.Fire(char)
-		errorCheck(response.Err)
+		result, err := backend.Fire(ctx, char)
 
 		// If shot were accepted by server, proceed
-		if response.StatusCode == 200 {
-			//Retrieve hit result
-			result, err := util.JSONGetParamFromJSON(response.Body, "result")
-			errorCheck(err)
-
+		if !errorCheck(err) {
 			//Set up go routine for text with result that shows up for 2 seconds and then dissapears
-			go DrawGUITextFor(40, 0, result.(string), nil, 2)
+			go DrawGUITextFor(ctx, 40, 0, result.Result, nil, 2)
 
 			//Checking the effect of player's shot
 			var effect gui.State
-			if result == "hit" || result == "sunk" {
+			if result.IsHit() {
 				effect = gui.Hit
 			} else {
 				effect = gui.Miss
 			}
 
-			//Creating an array interface with one shot for updating the enemy board
-			data := []string{char}
-			fire := make([]interface{}, len(data))
-			fire[0] = char
-
 			//Updating enemy board with player's shot effect
-			FillStatesWith(enemyBoard, &opponentStates, fire, effect, false)
+			FillStatesWith(enemyBoard, &opponentStates, []string{char}, effect, false)
+			assist.record(char, result.Result)
+
+			//After hit the player fires again, unless the last ship has just sunk
+			extraTurn = result.IsHit() && len(assist.targeting.Remaining()) > 0
+
+			//Whole sunk ship is known, so are the fields around it
+			if result.Result == models.ResultSunk {
+				enemySunk.update(enemyBoard, &opponentStates, assist.targeting.Sunk(), assist.targeting.Excluded())
+			}
+			stats.recordShot(result.Result, assist.targeting.Remaining())
+			panel.update(stats)
 		}
 		//Repeat until the end of the game
 	}
 
+	//Game is over, there is nothing to keep alive
+	keepAlive.Stop()
+	forfeit.remove()
+
 	//Cleaning up the boards adn nicks
 	ui.Remove(playerBoard)
 	ui.Remove(enemyBoard)
+	playerSunk.remove()
+	enemySunk.remove()
+	for _, label := range labels {
+		ui.Remove(label)
+	}
+	assist.remove()
+
+	panel.remove()
 
-	EndOfGame()
+	return gameOutcome{forfeited: forfeit.Forfeited(), stats: stats}
 }
 
-// EndOfGame is responsible for ending battleship game.
+// drawPlayerLabels draws nicks and descriptions of both players above their boards.
 //
-// It also prints if you won or lose.
-func EndOfGame() {
-	status := http.GameStatus()
-	errorCheck(status.Err)
-	dataMap, err := util.JSONToMap(status.Body)
-	errorCheck(err)
+//	Arguments:
+//
+// ctx - Context that can cancel the request.
+//
+//	Returns:
+//
+// []*gui.Text - Drawn labels (to be removed after the game).
+func drawPlayerLabels(ctx context.Context) []*gui.Text {
+	desc := GetDescriptions(ctx)
+
+	nickConfig := gui.NewTextConfig()
+	nickConfig.FgColor = gui.NewColor(230, 200, 60)
 
-	gameResultTest := DrawGUIText(1, 1, dataMap["last_game_status"].(string), nil)
-	WaitSeconds(5)
-	ui.Remove(gameResultTest)
+	labels := []*gui.Text{
+		DrawGUIText(1, 1, "You: "+desc.Nick, nickConfig),
+		DrawGUIText(50, 1, "Opponent: "+desc.Opponent, nickConfig),
+	}
+	labels = append(labels, DrawGUIWrappedText(1, 2, labelWidth, labelLines, desc.Desc, nil)...)
+	labels = append(labels, DrawGUIWrappedText(50, 2, labelWidth, labelLines, desc.OppDesc, nil)...)
+	return labels
 }
 
 // WaitSecond is function that forcing thread to get some sleep for 1 second.
@@ -375,10 +625,99 @@ func WaitSeconds(time int) {
 	}
 }
 
+// WaitSecondContext is waiting for 1 second unless context gets cancelled earlier.
+//
+//	Arguments:
+//
+// ctx - Context that can interrupt waiting.
+//
+//	Returns:
+//
+// bool - True if the whole second passed, false if context was cancelled.
+func WaitSecondContext(ctx context.Context) bool {
+	return WaitContext(ctx, time.Second)
+}
+
+// WaitContext is waiting for the given time unless context gets cancelled earlier.
+//
+//	Arguments:
+//
+// ctx - Context that can interrupt waiting.
+//
+// duration - Time to wait.
+//
+//	Returns:
+//
+// bool - True if the whole time passed, false if context was cancelled.
+func WaitContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// WaitSecondsContext is waiting for specific amount of time unless context gets cancelled earlier.
+//
+//	Arguments:
+//
+// ctx - Context that can interrupt waiting.
+//
+// time - Amount of time in seconds as integer
+//
+//	Returns:
+//
+// bool - True if the whole time passed, false if context was cancelled.
+func WaitSecondsContext(ctx context.Context, time int) bool {
+	for waiting := 0; waiting < time; waiting++ {
+		if !WaitSecondContext(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// ----- SERVER  ----------------------------------------------------------------------
+
+// GiveUpGame abandons the current game on the server.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// bool - True if server accepted abandoning of the game.
+func GiveUpGame(ctx context.Context) bool {
+	return !errorCheck(backend.GiveUp(ctx))
+}
+
+// GetDescriptions retrieves nicks and descriptions of the player and the opponent.
+//
+//	Arguments:
+//
+// ctx - Context that can cancel the request or limit its time.
+//
+//	Returns:
+//
+// models.DescResponse - Nicks and descriptions of both players.
+func GetDescriptions(ctx context.Context) models.DescResponse {
+	desc, err := backend.Descriptions(ctx)
+	errorCheck(err)
+	return desc
+}
+
 // ----- ERRORS -----------------------------------------------------------------------
 func errorCheck(err error) bool {
 	if err != nil {
-		errorOccured(err)
+		//Cancelled requests (game was left or closed) are not worth showing
+		if !errors.Is(err, context.Canceled) {
+			errorOccured(err)
+		}
 		return true
 	}
 	return false
@@ -387,15 +726,19 @@ func errorCheck(err error) bool {
 func errorOccured(err error) {
 	// TODO: In future, let error print to LOG
 
+	//Draw warning
+	DrawGUITextFor(uiContext, 50, 0, err.Error(), errorTextConfig(), 5)
+}
+
+// errorTextConfig returns configuration of the error messages.
+func errorTextConfig() *gui.TextConfig {
 	//If there is no config, create one.
 	if errorGUIConfig == nil {
 		errorGUIConfig = gui.NewTextConfig()
 		errorGUIConfig.BgColor = gui.Red
 		errorGUIConfig.FgColor = gui.Black
 	}
-
-	//Draw warning
-	DrawGUITextFor(50, 0, err.Error(), errorGUIConfig, 5)
+	return errorGUIConfig
 }
 
 // ----- INPUT   ----------------------------------------------------------------------
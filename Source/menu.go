@@ -0,0 +1,49 @@
+package source
+
+import (
+	"context"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- MENU    ----------------------------------------------------------------------
+
+// MainMenu shows the main menu and waits for the choice of the player.
+//
+//	Arguments:
+//
+// ctx - Context that closes the menu.
+//
+//	Returns:
+//
+// Screen - Chosen screen (ScreenQuit if ctx was cancelled).
+func MainMenu(ctx context.Context) Screen {
+	title := DrawGUIText(1, 1, "Sea Of Pirates", nil)
+	playButton := NewButton(1, 4, "Play (p)", 'p')
+	statsButton := NewButton(1, 6, "Statistics (s)", 's')
+	settingsButton := NewButton(1, 8, "Settings (o)", 'o')
+	quitButton := NewButton(1, 10, "Quit (q)", 'q')
+
+	drawables := []gui.Drawable{title, playButton, statsButton, settingsButton, quitButton}
+	for _, drawable := range drawables[1:] {
+		ui.Draw(drawable)
+	}
+	defer func() {
+		for _, drawable := range drawables {
+			ui.Remove(drawable)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ScreenQuit
+	case <-playButton.Clicks():
+		return ScreenPlacement
+	case <-statsButton.Clicks():
+		return ScreenStats
+	case <-settingsButton.Clicks():
+		return ScreenSettings
+	case <-quitButton.Clicks():
+		return ScreenQuit
+	}
+}
@@ -0,0 +1,180 @@
+package source
+
+import (
+	"fmt"
+
+	models "sea-of-pirates/Models"
+	util "sea-of-pirates/util"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- PANEL   ----------------------------------------------------------------------
+
+// gameStats counts the shots of both players during the game.
+//
+// shots, hits, streak, longest - Player's shots, hits (sunk included), current and
+// the longest series of hits.
+//
+// enemyRemaining - Sizes of the opponent's ships that are still afloat.
+//
+// fleet - Coordinates of the player's ships.
+//
+// oppShots, oppHits, oppLongest - The same for the opponent (counted from the status).
+//
+// ownRemaining - Sizes of the player's ships that are still afloat.
+type gameStats struct {
+	shots          int
+	hits           int
+	streak         int
+	longest        int
+	enemyRemaining []int
+	fleet          []string
+	oppShots       int
+	oppHits        int
+	oppLongest     int
+	ownRemaining   []int
+}
+
+// newGameStats creates statistics of the game with the whole fleets afloat.
+//
+//	Arguments:
+//
+// fleet - Coordinates of the player's ships.
+//
+// enemyRemaining - Sizes of the opponent's ships.
+func newGameStats(fleet []string, enemyRemaining []int) *gameStats {
+	s := &gameStats{fleet: fleet, enemyRemaining: enemyRemaining}
+	s.recordOppShots(nil)
+	return s
+}
+
+// recordShot counts the player's shot.
+//
+//	Arguments:
+//
+// result - One of models.Result* constants.
+//
+// enemyRemaining - Sizes of the opponent's ships still afloat after the shot.
+func (s *gameStats) recordShot(result string, enemyRemaining []int) {
+	s.shots++
+	if result == models.ResultMiss {
+		s.streak = 0
+	} else {
+		s.hits++
+		s.streak++
+		s.longest = max(s.longest, s.streak)
+	}
+	s.enemyRemaining = enemyRemaining
+}
+
+// recordOppShots recounts the opponent's statistics from all of the opponent's shots.
+//
+//	Arguments:
+//
+// oppShots - Every shot of the opponent in order.
+func (s *gameStats) recordOppShots(oppShots []string) {
+	ships := map[string]bool{}
+	for _, coord := range s.fleet {
+		ships[coord] = true
+	}
+
+	shot := map[string]bool{}
+	s.oppShots, s.oppHits, s.oppLongest = len(oppShots), 0, 0
+	streak := 0
+	for _, coord := range oppShots {
+		shot[coord] = true
+		if !ships[coord] {
+			streak = 0
+			continue
+		}
+		s.oppHits++
+		streak++
+		s.oppLongest = max(s.oppLongest, streak)
+	}
+
+	//Ship is afloat while any of its cells was not shot
+	s.ownRemaining = []int{}
+	for _, ship := range util.GroupShips(s.fleet) {
+		for _, coord := range ship {
+			if !shot[coord] {
+				s.ownRemaining = append(s.ownRemaining, len(ship))
+				break
+			}
+		}
+	}
+}
+
+// lines describes the statistics of both players.
+func (s *gameStats) lines() []string {
+	return []string{
+		"You",
+		fmt.Sprintf("  Shots: %d  Hits: %d (%s)", s.shots, s.hits, hitRatio(s.hits, s.shots)),
+		fmt.Sprintf("  Longest streak: %d", s.longest),
+		"  Enemy ships: " + shipsLeft(s.enemyRemaining),
+		"",
+		"Opponent",
+		fmt.Sprintf("  Shots: %d  Hits: %d (%s)", s.oppShots, s.oppHits, hitRatio(s.oppHits, s.oppShots)),
+		fmt.Sprintf("  Longest streak: %d", s.oppLongest),
+		"  Your ships: " + shipsLeft(s.ownRemaining),
+	}
+}
+
+// statsPanel shows the statistics of the game next to the boards.
+type statsPanel struct {
+	texts []*gui.Text
+}
+
+// newStatsPanel draws the panel with the statistics.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the panel.
+//
+// y - Integer y coordinate of the panel.
+//
+// stats - Statistics to show.
+func newStatsPanel(x int, y int, stats *gameStats) *statsPanel {
+	p := &statsPanel{texts: []*gui.Text{DrawGUIText(x, y, "Statistics (ships size:afloat/all)", nil)}}
+	for i := range stats.lines() {
+		p.texts = append(p.texts, DrawGUIText(x, y+2+i, "", nil))
+	}
+	p.update(stats)
+	return p
+}
+
+// update shows the current statistics.
+func (p *statsPanel) update(stats *gameStats) {
+	for i, line := range stats.lines() {
+		p.texts[i+1].SetText(line)
+	}
+}
+
+// remove removes the panel from the screen.
+func (p *statsPanel) remove() {
+	for _, text := range p.texts {
+		ui.Remove(text)
+	}
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// hitRatio returns percent of the hits (or "-" if there were no shots).
+func hitRatio(hits int, shots int) string {
+	if shots == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", 100*float64(hits)/float64(shots))
+}
+
+// shipsLeft describes how many ships of every size are afloat as size:afloat/all
+// (eg. "4:1/1 3:2/2 2:0/3 1:4/4").
+func shipsLeft(sizes []int) string {
+	counts := map[int]int{}
+	for _, size := range sizes {
+		counts[size]++
+	}
+	return fmt.Sprintf("4:%d/%d 3:%d/%d 2:%d/%d 1:%d/%d",
+		counts[4], util.ClassicFleet[4], counts[3], util.ClassicFleet[3],
+		counts[2], util.ClassicFleet[2], counts[1], util.ClassicFleet[1])
+}
@@ -0,0 +1,192 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	util "sea-of-pirates/util"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- PLACEMENT --------------------------------------------------------------------
+
+// PlaceFleet shows the placement screen where the player clicks cells of the board
+// to lay out the ships. Fleet is validated after every click and can be confirmed
+// only when it is legal. Confirming the empty board uses a random fleet instead.
+//
+//	Arguments:
+//
+// ctx - Context that stops the placement.
+//
+// initial - Fleet shown at start (can be nil for the empty board).
+//
+//	Returns:
+//
+// []string - Coordinates of the placed ships (eg. "A1", "B10").
+//
+// bool - False if placement was interrupted or player went back.
+func PlaceFleet(ctx context.Context, initial []string) ([]string, bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	//Drawing the placement screen
+	title := DrawGUIText(1, 1, "Place your fleet: click the fields to add or remove ships (confirm empty board for a random fleet)", nil)
+	board, states := CreateBoard(1, 3, nil, initial)
+	fleetText := DrawGUIText(50, 5, "", nil)
+	problemText := DrawGUIText(50, 7, "", nil)
+	confirmButton := NewButton(50, 10, "Confirm (c)", 'c')
+	clearButton := NewButton(50, 12, "Clear (x)", 'x')
+	randomButton := NewButton(50, 14, "Random (r)", 'r')
+	backButton := NewButton(50, 16, "Back (q)", 'q')
+	ui.Draw(confirmButton)
+	ui.Draw(clearButton)
+	ui.Draw(randomButton)
+	ui.Draw(backButton)
+
+	defer func() {
+		for _, drawable := range []gui.Drawable{title, board, fleetText, problemText, confirmButton, clearButton, randomButton, backButton} {
+			ui.Remove(drawable)
+		}
+	}()
+
+	problem := validatePlacement(states)
+	updatePlacementTexts(fleetText, problemText, states, problem, "")
+
+	fields := listenBoard(ctx, board)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+
+		case field, ok := <-fields:
+			if !ok {
+				return nil, false
+			}
+
+			//Toggling the ship on the clicked field
+			x, y, err := util.CoordToIntegers(field)
+			if errorCheck(err) {
+				continue
+			}
+			if states[x-1][y-1] == gui.Ship {
+				states[x-1][y-1] = gui.Empty
+			} else {
+				states[x-1][y-1] = gui.Ship
+			}
+			board.SetStates(states)
+
+			problem = validatePlacement(states)
+			updatePlacementTexts(fleetText, problemText, states, problem, "")
+
+		case <-backButton.Clicks():
+			return nil, false
+
+		case <-clearButton.Clicks():
+			states = SetupFillBoard(board)
+			problem = validatePlacement(states)
+			updatePlacementTexts(fleetText, problemText, states, problem, "")
+
+		case <-randomButton.Clicks():
+			coords, err := util.RandomFleet(fleetOptions)
+			if errorCheck(err) {
+				continue
+			}
+			states = SetupFillBoard(board)
+			FillStatesWith(board, &states, coords, gui.Ship, false)
+			problem = validatePlacement(states)
+			updatePlacementTexts(fleetText, problemText, states, problem, "")
+
+		case <-confirmButton.Clicks():
+			//Player does not want to place ships manually
+			if len(statesToCoords(states, gui.Ship)) == 0 {
+				coords, err := util.RandomFleet(fleetOptions)
+				if errorCheck(err) {
+					continue
+				}
+				return coords, true
+			}
+
+			if problem != "" {
+				updatePlacementTexts(fleetText, problemText, states, problem, "Fleet is not ready: ")
+				continue
+			}
+			return statesToCoords(states, gui.Ship), true
+		}
+	}
+}
+
+// updatePlacementTexts refreshes the summary of the fleet and the current problem.
+//
+//	Arguments:
+//
+// fleetText - Text with the number of ships of every size.
+//
+// problemText - Text with the problem of the fleet.
+//
+// states - States of the placement board.
+//
+// problem - Problem of the fleet (empty if fleet is legal).
+//
+// prefix - Additional text shown before the problem.
+func updatePlacementTexts(fleetText *gui.Text, problemText *gui.Text, states [10][10]gui.State, problem string, prefix string) {
+	counts := map[int]int{}
+	for _, ship := range util.GroupShips(statesToCoords(states, gui.Ship)) {
+		counts[len(ship)]++
+	}
+
+	fleetText.SetText(fmt.Sprintf("4-mast: %d/1  3-mast: %d/2  2-mast: %d/3  1-mast: %d/4",
+		counts[4], counts[3], counts[2], counts[1]))
+
+	if problem == "" {
+		problemText.SetText("Fleet is ready!")
+		return
+	}
+	problemText.SetText(prefix + problem)
+}
+
+// validatePlacement checks if ships on the board make the classic fleet
+// (one 4-mast, two 3-mast, three 2-mast, four 1-mast) without touching ships.
+//
+//	Arguments:
+//
+// states - States of the placement board.
+//
+//	Returns:
+//
+// string - Description of the first problem (empty if fleet is legal).
+func validatePlacement(states [10][10]gui.State) string {
+	var problems util.FleetErrors
+	if !errors.As(util.ValidateFleet(statesToCoords(states, gui.Ship)), &problems) {
+		return ""
+	}
+
+	if len(problems) > 1 {
+		return fmt.Sprintf("%s (+%d more)", problems[0].Message, len(problems)-1)
+	}
+	return problems[0].Message
+}
+
+// statesToCoords returns coordinates of all the fields with the given state.
+//
+//	Arguments:
+//
+// states - States of the board.
+//
+// state - State to look for.
+//
+//	Returns:
+//
+// []string - Coordinates of the fields (eg. "A1", "B10").
+func statesToCoords(states [10][10]gui.State, state gui.State) []string {
+	coords := []string{}
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if states[x][y] == state {
+				coords = append(coords, util.IntegersToCoord(x+1, y+1))
+			}
+		}
+	}
+	return coords
+}
@@ -0,0 +1,300 @@
+package source
+
+import (
+	"context"
+
+	models "sea-of-pirates/Models"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- SCREENS ----------------------------------------------------------------------
+
+// Screen identifies the screen of the terminal client.
+type Screen string
+
+// Screens of the client. ScreenBack is not a real screen, it returns to the previous one.
+const (
+	ScreenMenu      Screen = "menu"
+	ScreenPlacement Screen = "placement"
+	ScreenLobby     Screen = "lobby"
+	ScreenGame      Screen = "game"
+	ScreenResults   Screen = "results"
+	ScreenStats     Screen = "stats"
+	ScreenSettings  Screen = "settings"
+	ScreenQuit      Screen = "quit"
+	ScreenBack      Screen = "back"
+)
+
+// screens is the state machine that switches the screens of the client.
+//
+// profile - Profile of the player with the preferred opponent (wpbot or lobby).
+//
+// fixedFleet - Fleet was given in advance (eg. from file), so placement is skipped.
+//
+// fleet - Fleet of the last game (shown again on "play again").
+//
+// game - Request of the current game (opponent chosen in the lobby).
+//
+// outcome - Result of the last game.
+//
+// history - Previous screens for the back navigation.
+//
+// failure - Why the last game could not be started (nil after the played game).
+type screens struct {
+	profile    models.StartGameRequest
+	fixedFleet bool
+	fleet      []string
+	game       models.StartGameRequest
+	outcome    gameOutcome
+	history    []Screen
+	failure    error
+}
+
+// newScreens creates the state machine starting from the main menu.
+//
+//	Arguments:
+//
+// request - Profile of the player. If coords are set, placement is skipped.
+func newScreens(request models.StartGameRequest) *screens {
+	fleet := request.Coords
+	if len(fleet) == 0 {
+		fleet = defaultFleet
+	}
+	return &screens{profile: request, fixedFleet: len(request.Coords) > 0, fleet: fleet}
+}
+
+// run shows the screens one after another until the player quits or ctx is cancelled.
+//
+//	Arguments:
+//
+// ctx - Context that stops the client.
+//
+//	Returns:
+//
+// error - Why the last game could not be started (nil if it was played).
+func (s *screens) run(ctx context.Context) error {
+	current := ScreenMenu
+	for current != ScreenQuit && ctx.Err() == nil {
+		next := s.show(ctx, current)
+
+		switch next {
+		case ScreenBack:
+			//Returning to the previous screen (or menu if there is none)
+			next = ScreenMenu
+			if len(s.history) > 0 {
+				next = s.history[len(s.history)-1]
+				s.history = s.history[:len(s.history)-1]
+			}
+		case ScreenMenu, ScreenGame:
+			//There is no way back from the menu or into the finished game
+			s.history = nil
+		default:
+			if s.remembered(current) {
+				s.history = append(s.history, current)
+			}
+		}
+		current = next
+	}
+	return s.failure
+}
+
+// remembered checks if the screen can be returned to. The game can't be entered
+// again and placement of the fleet given in advance is never shown.
+func (s *screens) remembered(screen Screen) bool {
+	return screen != ScreenGame && (screen != ScreenPlacement || !s.fixedFleet)
+}
+
+// show shows the single screen.
+//
+//	Returns:
+//
+// Screen - Screen that should be shown next.
+func (s *screens) show(ctx context.Context, screen Screen) Screen {
+	switch screen {
+	case ScreenMenu:
+		return MainMenu(ctx)
+
+	case ScreenPlacement:
+		//Fleet given in advance does not need placing
+		if s.fixedFleet {
+			return s.afterPlacement()
+		}
+		coords, placed := PlaceFleet(ctx, s.fleet)
+		if !placed {
+			return ScreenBack
+		}
+		s.fleet = coords
+		return s.afterPlacement()
+
+	case ScreenLobby:
+		request, chosen := ChooseOpponent(ctx, s.newGame())
+		if !chosen {
+			return ScreenBack
+		}
+		s.game = request
+		return ScreenGame
+
+	case ScreenGame:
+		outcome, err := playGame(ctx, s.game)
+		if err != nil {
+			//Interrupting is not a failure of the game
+			if ctx.Err() == nil {
+				s.failure = err
+			}
+			return ScreenMenu
+		}
+		s.failure = nil
+		s.outcome = outcome
+		return ScreenResults
+
+	case ScreenResults:
+		return ShowResults(ctx, s.outcome)
+
+	case ScreenStats:
+		ShowLeaderboard(ctx, s.profile.Nick)
+		return ScreenBack
+
+	case ScreenSettings:
+		ShowSettings(ctx, &s.profile)
+		return ScreenBack
+	}
+	return ScreenQuit
+}
+
+// afterPlacement chooses the lobby or the game right away, if the opponent is known.
+func (s *screens) afterPlacement() Screen {
+	s.game = s.newGame()
+	if usesLobby(s.game) {
+		return ScreenLobby
+	}
+	return ScreenGame
+}
+
+// newGame returns the request of the new game with the profile and the placed fleet.
+func (s *screens) newGame() models.StartGameRequest {
+	request := s.profile
+	request.Coords = s.fleet
+	return request
+}
+
+// ----- RESULTS ----------------------------------------------------------------------
+
+// ShowResults shows the result of the last game and lets the player choose what's next.
+//
+//	Arguments:
+//
+// ctx - Context that closes the screen.
+//
+// outcome - Outcome of the game.
+//
+//	Returns:
+//
+// Screen - ScreenPlacement (play again), ScreenStats, ScreenMenu or ScreenQuit.
+func ShowResults(ctx context.Context, outcome gameOutcome) Screen {
+	status, err := backend.GameStatus(ctx)
+	errorCheck(err)
+	result := "Game over: " + status.LastGameStatus
+	if outcome.forfeited {
+		result += " (you gave up)"
+	}
+
+	resultText := DrawGUIText(1, 1, result, nil)
+	againButton := NewButton(1, 4, "Play again (p)", 'p')
+	statsButton := NewButton(1, 6, "Statistics (s)", 's')
+	menuButton := NewButton(1, 8, "Menu (m)", 'm')
+	quitButton := NewButton(1, 10, "Quit (q)", 'q')
+
+	drawables := []gui.Drawable{resultText, againButton, statsButton, menuButton, quitButton}
+	for _, drawable := range drawables[1:] {
+		ui.Draw(drawable)
+	}
+
+	//Summary of the game next to the buttons
+	if outcome.stats != nil {
+		panel := newStatsPanel(30, 4, outcome.stats)
+		defer panel.remove()
+	}
+	defer func() {
+		for _, drawable := range drawables {
+			ui.Remove(drawable)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ScreenQuit
+	case <-againButton.Clicks():
+		return ScreenPlacement
+	case <-statsButton.Clicks():
+		return ScreenStats
+	case <-menuButton.Clicks():
+		return ScreenMenu
+	case <-quitButton.Clicks():
+		return ScreenQuit
+	}
+}
+
+// ----- SETTINGS ---------------------------------------------------------------------
+
+// ShowSettings lets the player choose the opponent, the aiming assistance and
+// firing on timeout.
+// Settings last until the program exits.
+//
+//	Arguments:
+//
+// ctx - Context that closes the screen.
+//
+// profile - Profile of the player (wpbot is changed in place).
+func ShowSettings(ctx context.Context, profile *models.StartGameRequest) {
+	title := DrawGUIText(1, 1, "Settings of "+profile.Nick, nil)
+	opponentButton := NewButton(1, 4, "", 'b')
+	hintButton := NewButton(1, 6, "", 'h')
+	autoButton := NewButton(1, 8, "", 'a')
+	timeoutButton := NewButton(1, 10, "", 't')
+	backButton := NewButton(1, 13, "Back (q)", 'q')
+
+	update := func() {
+		opponent := "lobby"
+		if profile.TargetNick != "" {
+			opponent = profile.TargetNick
+		} else if profile.WPBot {
+			opponent = "WP bot"
+		}
+		opponentButton.SetLabel("Opponent: " + opponent + " (b)")
+		hintButton.SetLabel("Hint at start: " + onOff(assistHint) + " (h)")
+		autoButton.SetLabel("Auto-fire at start: " + onOff(assistAuto) + " (a)")
+		timeoutButton.SetLabel("Fire best guess when time runs out: " + onOff(fireOnTimeout) + " (t)")
+	}
+	update()
+
+	drawables := []gui.Drawable{title, opponentButton, hintButton, autoButton, timeoutButton, backButton}
+	for _, drawable := range drawables[1:] {
+		ui.Draw(drawable)
+	}
+	defer func() {
+		for _, drawable := range drawables {
+			ui.Remove(drawable)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-opponentButton.Clicks():
+			//Switching between the WP bot and the lobby (target is forgotten)
+			profile.WPBot = !profile.WPBot || profile.TargetNick != ""
+			profile.TargetNick = ""
+		case <-hintButton.Clicks():
+			assistHint = !assistHint
+		case <-autoButton.Clicks():
+			assistAuto = !assistAuto
+		case <-timeoutButton.Clicks():
+			fireOnTimeout = !fireOnTimeout
+		case <-backButton.Clicks():
+			return
+		}
+		update()
+	}
+}
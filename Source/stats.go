@@ -0,0 +1,167 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	models "sea-of-pirates/Models"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- STATS   ----------------------------------------------------------------------
+
+// leaderboardRows is the number of players shown on the leaderboard (they can be picked
+// with keys 1-9 and 0 for the tenth).
+const leaderboardRows = 10
+
+// ShowLeaderboard shows the top players of the server. Clicking the player (or
+// pressing 1-9 and 0) opens the card of the player, the leaderboard is hidden meanwhile.
+//
+//	Arguments:
+//
+// ctx - Context that closes the screen.
+//
+// nick - Nick of the player, whose position is shown below the table (can be empty).
+//
+//	Returns:
+//
+// bool - False if screen was interrupted by ctx.
+func ShowLeaderboard(ctx context.Context, nick string) bool {
+	title := DrawGUIText(1, 1, "Leaderboard - top 10 (click the player or press 1-9 and 0 to see the card)", nil)
+	header := DrawGUIText(4, 3, fmt.Sprintf("%-5s %-25s %6s %6s %7s", "RANK", "NICK", "GAMES", "WINS", "POINTS"), nil)
+	list := NewList(1, 4, 70, leaderboardRows)
+	info := DrawGUIText(1, 5+leaderboardRows, "Loading statistics...", nil)
+	cardButton := NewButton(1, 7+leaderboardRows, "My card (m)", 'm')
+	backButton := NewButton(20, 7+leaderboardRows, "Back (q)", 'q')
+	ui.Draw(list)
+	ui.Draw(cardButton)
+	ui.Draw(backButton)
+
+	drawables := []gui.Drawable{title, header, list, info, cardButton, backButton}
+	defer func() {
+		for _, drawable := range drawables {
+			ui.Remove(drawable)
+		}
+	}()
+
+	//Card is shown on the empty screen, leaderboard comes back after it
+	showCard := func(nick string) bool {
+		for _, drawable := range drawables {
+			ui.Remove(drawable)
+		}
+		defer func() {
+			for _, drawable := range drawables {
+				ui.Draw(drawable)
+			}
+		}()
+		return ShowPlayerCard(ctx, nick)
+	}
+
+	//Downloading the leaderboard and position of the player
+	players := []models.PlayerStats{}
+	stats, response := client.Stats(ctx)
+	if response.Err != nil {
+		info.SetText("Statistics are not available: " + response.Err.Error())
+	} else {
+		players = stats.Stats[:min(len(stats.Stats), leaderboardRows)]
+		items := []string{}
+		for _, player := range players {
+			items = append(items, fmt.Sprintf("%-5d %-25s %6d %6d %7d", player.Rank, player.Nick, player.Games, player.Wins, player.Points))
+		}
+		list.SetItems(items)
+		info.SetText(playerPosition(ctx, nick))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+
+		case row := <-list.Picks():
+			if row < len(players) && !showCard(players[row].Nick) {
+				return false
+			}
+
+		case <-cardButton.Clicks():
+			if nick != "" && !showCard(nick) {
+				return false
+			}
+
+		case <-backButton.Clicks():
+			return true
+		}
+	}
+}
+
+// ShowPlayerCard shows the statistics of the single player.
+//
+//	Arguments:
+//
+// ctx - Context that closes the screen.
+//
+// nick - Nick of the player.
+//
+//	Returns:
+//
+// bool - False if screen was interrupted by ctx.
+func ShowPlayerCard(ctx context.Context, nick string) bool {
+	lines := []string{"Player card: " + nick, ""}
+	stats, response := client.StatsOfPlayer(ctx, nick)
+	if response.Err != nil {
+		lines = append(lines, "Statistics are not available: "+response.Err.Error())
+	} else {
+		player := stats.Stats
+		lines = append(lines,
+			fmt.Sprintf("Rank:      %d", player.Rank),
+			fmt.Sprintf("Points:    %d", player.Points),
+			fmt.Sprintf("Games:     %d", player.Games),
+			fmt.Sprintf("Wins:      %d", player.Wins),
+			fmt.Sprintf("Losses:    %d", player.Games-player.Wins),
+			fmt.Sprintf("Win ratio: %s", winRatio(player)),
+		)
+	}
+
+	texts := []*gui.Text{}
+	for i, line := range lines {
+		texts = append(texts, DrawGUIText(1, 1+i, line, nil))
+	}
+	backButton := NewButton(1, 3+len(lines), "Back (q)", 'q')
+	ui.Draw(backButton)
+
+	defer func() {
+		for _, text := range texts {
+			ui.Remove(text)
+		}
+		ui.Remove(backButton)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-backButton.Clicks():
+		return true
+	}
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// playerPosition describes the position of the player on the server.
+func playerPosition(ctx context.Context, nick string) string {
+	if nick == "" {
+		return ""
+	}
+	stats, response := client.StatsOfPlayer(ctx, nick)
+	if response.Err != nil {
+		return nick + " has no statistics yet"
+	}
+	return fmt.Sprintf("%s is #%d with %d points", nick, stats.Stats.Rank, stats.Stats.Points)
+}
+
+// winRatio returns percent of the won games (or "-" if there were no games).
+func winRatio(player models.PlayerStats) string {
+	if player.Games == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", 100*float64(player.Wins)/float64(player.Games))
+}
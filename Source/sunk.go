@@ -0,0 +1,130 @@
+package source
+
+import (
+	util "sea-of-pirates/util"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- SUNK    ----------------------------------------------------------------------
+
+// sunkColor is the color of the fields of the sunk ships.
+var sunkColor = gui.NewColor(110, 20, 20)
+
+// sunkMarks draws the sunk ships on top of the board. Board knows only four states,
+// so the fields of the sunk ships are covered with the texts of their own color.
+//
+// x, y - Coordinates of the board on the screen.
+//
+// marks - Drawn fields by coordinate.
+type sunkMarks struct {
+	x     int
+	y     int
+	marks map[string]*gui.Text
+}
+
+// newSunkMarks creates the marks for the board.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the board.
+//
+// y - Integer y coordinate of the board.
+func newSunkMarks(x int, y int) *sunkMarks {
+	return &sunkMarks{x: x, y: y, marks: map[string]*gui.Text{}}
+}
+
+// update draws the sunk ships and marks the fields around them as missed (they
+// can't contain any ship).
+//
+//	Arguments:
+//
+// board - Board with the ships.
+//
+// states - States connected with the board.
+//
+// sunk - Coordinates of the fields of the sunk ships.
+//
+// around - Coordinates of the fields next to the sunk ships.
+func (m *sunkMarks) update(board *gui.Board, states *[10][10]gui.State, sunk []string, around []string) {
+	cfg := gui.NewTextConfig()
+	cfg.FgColor = gui.White
+	cfg.BgColor = sunkColor
+	applyColor(&cfg.BgColor, boardColors.Sunk)
+
+	for _, coord := range sunk {
+		x, y, err := util.CoordToIntegers(coord)
+		if _, drawn := m.marks[coord]; drawn || err != nil {
+			continue
+		}
+		//Fields are 3 characters wide and separated by the ruler lines
+		m.marks[coord] = DrawGUIText(m.x+4*x, m.y+2*y, " # ", cfg)
+	}
+
+	for _, coord := range around {
+		if x, y, err := util.CoordToIntegers(coord); err == nil && states[x-1][y-1] == gui.Empty {
+			states[x-1][y-1] = gui.Miss
+		}
+	}
+	board.SetStates(*states)
+}
+
+// remove removes the marks from the screen.
+func (m *sunkMarks) remove() {
+	for _, mark := range m.marks {
+		ui.Remove(mark)
+	}
+}
+
+// sunkShips finds the player's ships that were sunk by the opponent.
+//
+//	Arguments:
+//
+// fleet - Coordinates of the player's ships.
+//
+// oppShots - Every shot of the opponent.
+//
+//	Returns:
+//
+// []string - Coordinates of the fields of the sunk ships.
+//
+// []string - Coordinates of the fields next to the sunk ships (without ships).
+func sunkShips(fleet []string, oppShots []string) ([]string, []string) {
+	grouped := util.GroupShips(fleet)
+	ships := map[string]bool{}
+	for _, ship := range grouped {
+		for _, coord := range ship {
+			ships[coord] = true
+		}
+	}
+	shot := map[string]bool{}
+	for _, coord := range oppShots {
+		shot[coord] = true
+	}
+
+	sunk := []string{}
+	around := []string{}
+	for _, ship := range grouped {
+		afloat := false
+		for _, coord := range ship {
+			afloat = afloat || !shot[coord]
+		}
+		if afloat {
+			continue
+		}
+
+		sunk = append(sunk, ship...)
+		for _, coord := range ship {
+			x, y, _ := util.CoordToIntegers(coord)
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					next := util.IntegersToCoord(x+dx, y+dy)
+					if x+dx >= 1 && x+dx <= 10 && y+dy >= 1 && y+dy <= 10 && !ships[next] {
+						around = append(around, next)
+					}
+				}
+			}
+		}
+	}
+	return sunk, around
+}
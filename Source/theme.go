@@ -0,0 +1,74 @@
+package source
+
+import (
+	config "sea-of-pirates/Config"
+	util "sea-of-pirates/util"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- THEME   ----------------------------------------------------------------------
+
+// boardColors are the colors of all the boards (empty fields mean default colors).
+var boardColors config.Colors
+
+// defaultFleet is the fleet shown on the placement screen at start (can be nil).
+var defaultFleet []string
+
+// fleetOptions are the constraints of the random fleets.
+var fleetOptions util.FleetOptions
+
+// SetColors changes the colors of the boards created from now on.
+//
+//	Arguments:
+//
+// colors - Colors written as "#RRGGBB" (empty means the default color).
+//
+//	Returns:
+//
+// error - If any color can't be read.
+func SetColors(colors config.Colors) error {
+	for _, color := range []string{colors.Ship, colors.Hit, colors.Miss, colors.Hint, colors.Sunk} {
+		if _, _, _, err := config.ParseColor(color); color != "" && err != nil {
+			return err
+		}
+	}
+	boardColors = colors
+	return nil
+}
+
+// SetDefaultFleet changes the fleet that is shown on the placement screen at start.
+//
+//	Arguments:
+//
+// coords - Coordinates of the fleet (nil for the empty board).
+func SetDefaultFleet(coords []string) {
+	defaultFleet = coords
+}
+
+// SetFleetOptions changes the constraints of the random fleets (seed is ignored, so
+// every fleet is different).
+//
+//	Arguments:
+//
+// options - Constraints of the fleet.
+func SetFleetOptions(options util.FleetOptions) {
+	options.Seed = 0
+	fleetOptions = options
+}
+
+// boardConfig returns the configuration of the board with the colors of the theme.
+func boardConfig() *gui.BoardConfig {
+	cfg := gui.NewBoardConfig()
+	applyColor(&cfg.ShipColor, boardColors.Ship)
+	applyColor(&cfg.HitColor, boardColors.Hit)
+	applyColor(&cfg.MissColor, boardColors.Miss)
+	return cfg
+}
+
+// applyColor overwrites the color if the written one is set and valid.
+func applyColor(target *gui.Color, color string) {
+	if r, g, b, err := config.ParseColor(color); color != "" && err == nil {
+		*target = gui.NewColor(r, g, b)
+	}
+}
@@ -0,0 +1,102 @@
+package source
+
+import (
+	"fmt"
+	"time"
+
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- TIMER   ----------------------------------------------------------------------
+
+// timerRefresh is the time between two redraws of the countdown.
+const timerRefresh = 200 * time.Millisecond
+
+// Remaining seconds below which the countdown changes its color.
+const (
+	timerWarning  = 20
+	timerCritical = 10
+)
+
+// timeoutFireMargin is the time before the deadline when the best guess is fired
+// automatically (if enabled on the settings screen).
+const timeoutFireMargin = 3 * time.Second
+
+// fireOnTimeout enables firing the best guess when the turn is about to expire.
+var fireOnTimeout bool
+
+// Colors of the countdown.
+var (
+	timerColor         = gui.NewColor(120, 200, 120)
+	timerWarningColor  = gui.NewColor(230, 200, 60)
+	timerCriticalColor = gui.NewColor(230, 70, 70)
+)
+
+// turnTimer is the live countdown of the player's turn. It does not draw in the
+// background: the loop waiting for the shot redraws it on every tick.
+//
+// text - Label with the remaining time.
+//
+// deadline - Time when the turn ends.
+//
+// ticker - Ticker of the redraws.
+type turnTimer struct {
+	text     *gui.Text
+	deadline time.Time
+	ticker   *time.Ticker
+}
+
+// startTurnTimer draws the countdown to the deadline and starts ticking.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the countdown.
+//
+// y - Integer y coordinate of the countdown.
+//
+// deadline - Time when the turn ends.
+//
+//	Returns:
+//
+// *turnTimer - Running countdown.
+func startTurnTimer(x int, y int, deadline time.Time) *turnTimer {
+	t := &turnTimer{
+		text:     DrawGUIText(x, y, "", nil),
+		deadline: deadline,
+		ticker:   time.NewTicker(timerRefresh),
+	}
+	t.update()
+	return t
+}
+
+// Ticks returns the channel that receives a value whenever the countdown should be
+// redrawn with update (nil for no countdown, so it never fires in select).
+func (t *turnTimer) Ticks() <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.ticker.C
+}
+
+// update shows the remaining time in the color depending on how much is left.
+func (t *turnTimer) update() {
+	left := int(time.Until(t.deadline).Round(time.Second).Seconds())
+	left = max(left, 0)
+
+	color := timerColor
+	switch {
+	case left <= timerCritical:
+		color = timerCriticalColor
+	case left <= timerWarning:
+		color = timerWarningColor
+	}
+
+	t.text.SetFgColor(color)
+	t.text.SetText(fmt.Sprintf("Time: %2ds", left))
+}
+
+// stop stops the countdown and removes it from the screen.
+func (t *turnTimer) stop() {
+	t.ticker.Stop()
+	ui.Remove(t.text)
+}
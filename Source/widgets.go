@@ -0,0 +1,276 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+	gui "github.com/grupawp/warships-gui/v2"
+)
+
+// ----- WIDGETS ----------------------------------------------------------------------
+
+// Button is a clickable label that can also be pressed with a keyboard shortcut.
+// It implements gui.Drawable, so it is drawn and removed with ui.Draw and ui.Remove.
+//
+// id - Identifier required by the GUI.
+//
+// area - Clickable background of the button.
+//
+// text - Label of the button.
+//
+// clicks - Channel receiving a value on every click (dropped if nobody listens).
+type Button struct {
+	id     uuid.UUID
+	area   *buttonArea
+	text   *tl.Text
+	clicks chan struct{}
+}
+
+// buttonArea is the termloop entity that reacts on mouse clicks and the shortcut.
+type buttonArea struct {
+	*tl.Rectangle
+	key    rune
+	clicks chan<- struct{}
+}
+
+// NewButton creates a button with the label surrounded by brackets.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the button.
+//
+// y - Integer y coordinate of the button.
+//
+// label - Text of the button.
+//
+// key - Keyboard shortcut (0 means no shortcut).
+//
+//	Returns:
+//
+// *Button - Pointer at button that is not yet drawn.
+func NewButton(x int, y int, label string, key rune) *Button {
+	cfg := gui.NewTextConfig()
+	fg, bg := colorToAttr(cfg.FgColor), colorToAttr(cfg.BgColor)
+
+	label = "[ " + label + " ]"
+	clicks := make(chan struct{})
+	return &Button{
+		id:     uuid.New(),
+		area:   &buttonArea{Rectangle: tl.NewRectangle(x, y, len(label), 1, bg), key: key, clicks: clicks},
+		text:   tl.NewText(x, y, label, fg, bg),
+		clicks: clicks,
+	}
+}
+
+// SetLabel changes the text of the button (clickable area is resized as well).
+//
+//	Arguments:
+//
+// label - New text of the button.
+func (b *Button) SetLabel(label string) {
+	label = "[ " + label + " ]"
+	b.text.SetText(label)
+	b.area.SetSize(len(label), 1)
+}
+
+// Clicks returns the channel that receives a value whenever button is pressed.
+func (b *Button) Clicks() <-chan struct{} {
+	return b.clicks
+}
+
+// ID returns the identifier of the button required by the GUI.
+func (b *Button) ID() uuid.UUID {
+	return b.id
+}
+
+// Drawables returns the termloop entities of the button (used by ui.Draw and ui.Remove).
+func (b *Button) Drawables() []tl.Drawable {
+	return []tl.Drawable{b.area, b.text}
+}
+
+// Tick processes the events of the terminal (mouse click or shortcut key).
+func (a *buttonArea) Tick(e tl.Event) {
+	pressed := false
+	switch {
+	case e.Type == tl.EventMouse && e.Key == tl.MouseLeft:
+		x, y := a.Position()
+		w, h := a.Size()
+		pressed = e.MouseX >= x && e.MouseX < x+w && e.MouseY >= y && e.MouseY < y+h
+	case e.Type == tl.EventKey && a.key != 0:
+		pressed = e.Ch == a.key
+	}
+
+	if pressed {
+		select {
+		case a.clicks <- struct{}{}:
+		default:
+			// drop
+		}
+	}
+}
+
+// List shows rows of text that can be picked with a mouse click or keys 1-9 (and 0
+// for the tenth row).
+// It implements gui.Drawable, so it is drawn and removed with ui.Draw and ui.Remove.
+//
+// id - Identifier required by the GUI.
+//
+// area - Clickable background of the list.
+//
+// texts - One text per row.
+//
+// width - Maximal length of the row.
+//
+// picks - Channel receiving index of every picked row (dropped if nobody listens).
+type List struct {
+	id    uuid.UUID
+	area  *listArea
+	texts []*tl.Text
+	width int
+	picks chan int
+}
+
+// listArea is the termloop entity that reacts on mouse clicks and number keys.
+type listArea struct {
+	*tl.Rectangle
+	count atomic.Int32
+	picks chan<- int
+}
+
+// NewList creates an empty list.
+//
+//	Arguments:
+//
+// x - Integer x coordinate of the list.
+//
+// y - Integer y coordinate of the list.
+//
+// width - Maximal length of the row.
+//
+// rows - Number of visible rows.
+//
+//	Returns:
+//
+// *List - Pointer at list that is not yet drawn.
+func NewList(x int, y int, width int, rows int) *List {
+	cfg := gui.NewTextConfig()
+	fg, bg := colorToAttr(cfg.FgColor), colorToAttr(cfg.BgColor)
+
+	picks := make(chan int)
+	list := &List{
+		id:    uuid.New(),
+		area:  &listArea{Rectangle: tl.NewRectangle(x, y, width, rows, bg), picks: picks},
+		width: width,
+		picks: picks,
+	}
+	for row := 0; row < rows; row++ {
+		list.texts = append(list.texts, tl.NewText(x, y+row, "", fg, bg))
+	}
+	return list
+}
+
+// SetItems replaces the rows of the list. Rows are numbered with their keys (the
+// tenth row is 0) and items that do not fit are skipped.
+//
+//	Arguments:
+//
+// items - Text of every row.
+func (l *List) SetItems(items []string) {
+	count := min(len(items), len(l.texts))
+	for row, text := range l.texts {
+		line := ""
+		if row < count {
+			line = fmt.Sprintf("%d. %s", (row+1)%10, items[row])
+		}
+		if len(line) > l.width {
+			line = line[:l.width]
+		}
+		text.SetText(line)
+	}
+	l.area.count.Store(int32(count))
+}
+
+// Picks returns the channel that receives index of the row whenever it is picked.
+func (l *List) Picks() <-chan int {
+	return l.picks
+}
+
+// ID returns the identifier of the list required by the GUI.
+func (l *List) ID() uuid.UUID {
+	return l.id
+}
+
+// Drawables returns the termloop entities of the list (used by ui.Draw and ui.Remove).
+func (l *List) Drawables() []tl.Drawable {
+	drawables := []tl.Drawable{l.area}
+	for _, text := range l.texts {
+		drawables = append(drawables, text)
+	}
+	return drawables
+}
+
+// Tick processes the events of the terminal (mouse click on the row or its number).
+func (a *listArea) Tick(e tl.Event) {
+	row := -1
+	switch {
+	case e.Type == tl.EventMouse && e.Key == tl.MouseLeft:
+		x, y := a.Position()
+		w, h := a.Size()
+		if e.MouseX >= x && e.MouseX < x+w && e.MouseY >= y && e.MouseY < y+h {
+			row = e.MouseY - y
+		}
+	case e.Type == tl.EventKey && e.Ch >= '1' && e.Ch <= '9':
+		row = int(e.Ch - '1')
+	case e.Type == tl.EventKey && e.Ch == '0':
+		row = 9
+	}
+
+	if row < 0 || row >= int(a.count.Load()) {
+		return
+	}
+	select {
+	case a.picks <- row:
+	default:
+		// drop
+	}
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// colorToAttr translates the color of the GUI into the termloop attribute.
+func colorToAttr(c gui.Color) tl.Attr {
+	return tl.RgbTo256Color(int(c.Red), int(c.Green), int(c.Blue))
+}
+
+// listenBoard forwards every clicked field of the board into the channel until ctx is done.
+//
+//	Arguments:
+//
+// ctx - Context that stops listening.
+//
+// board - Board to listen to.
+//
+//	Returns:
+//
+// <-chan string - Channel with clicked coordinates.
+func listenBoard(ctx context.Context, board *gui.Board) <-chan string {
+	fields := make(chan string)
+	go func() {
+		defer close(fields)
+		for {
+			field := board.Listen(ctx)
+			if field == "" {
+				return
+			}
+			select {
+			case fields <- field:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return fields
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	server "sea-of-pirates/Server"
+)
+
+// Standalone local game server. Point the client at it with
+// http.SetServerURL("http://localhost:8080/api/").
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Local server is listening on %s (API under %s)\n", *addr, server.APIPrefix)
+	if err := server.ListenAndServe(ctx, *addr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	bot "sea-of-pirates/Bot"
+	config "sea-of-pirates/Config"
+	http "sea-of-pirates/HTTP"
+	models "sea-of-pirates/Models"
+	server "sea-of-pirates/Server"
+	source "sea-of-pirates/Source"
+	util "sea-of-pirates/util"
+)
+
+// ----- FLAGS   ----------------------------------------------------------------------
+
+// newFlagSet creates the flags of the command with the help text.
+//
+//	Arguments:
+//
+// name - Name of the command.
+//
+// usage - Arguments of the command shown in the help text.
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage:\n  sea-of-pirates %s %s\n\nFlags:\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the arguments and translates parsing errors into errUsage
+// (flag package has already reported them).
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+// usageError reports wrong usage of the command and prints its help text.
+func usageError(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), "sea-of-pirates %s: %s\n\n", flags.Name(), fmt.Sprintf(format, args...))
+	flags.Usage()
+	return errUsage
+}
+
+// serverFlags are the flags of every command that talks to the game server.
+type serverFlags struct {
+	configPath string
+	server     string
+	timeout    time.Duration
+}
+
+// register adds the server flags to the flag set.
+func (s *serverFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&s.configPath, "config", "", "config file (default $"+config.EnvConfig+" or config.json in the user's config directory)")
+	flags.StringVar(&s.server, "server", "", "URL of the game server API (default from config)")
+	flags.DurationVar(&s.timeout, "timeout", 0, "limit of the single request (default from config)")
+}
+
+// load reads the config and overrides it with the flags that were given explicitly.
+//
+//	Returns:
+//
+// config.Config - Validated configuration.
+//
+// error - If config can't be read or is not valid.
+func (s *serverFlags) load(flags *flag.FlagSet) (config.Config, error) {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return cfg, err
+	}
+	s.override(flags, &cfg)
+	return cfg, cfg.Validate()
+}
+
+// override overwrites the config with the server flags that were given explicitly.
+func (s *serverFlags) override(flags *flag.FlagSet, cfg *config.Config) {
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "server":
+			cfg.Server.URL = config.ServerURL(s.server)
+		case "timeout":
+			cfg.Server.Timeout = config.Duration(s.timeout)
+		}
+	})
+}
+
+// newClient creates the client of the game server from the config.
+func newClient(cfg config.Config) *http.Client {
+	c := http.NewClient(cfg.Server.URL)
+	c.SetTimeout(time.Duration(cfg.Server.Timeout))
+	return c
+}
+
+// gameFlags are the flags shared by the commands that play the game.
+type gameFlags struct {
+	serverFlags
+	nick       string
+	desc       string
+	target     string
+	wpbot      bool
+	fleetFile  string
+	offline    bool
+	avoidEdges bool
+	corner     string
+}
+
+// register adds the game flags to the flag set.
+func (g *gameFlags) register(flags *flag.FlagSet) {
+	g.serverFlags.register(flags)
+	flags.StringVar(&g.nick, "nick", "", "nick of the player (default from config)")
+	flags.StringVar(&g.desc, "desc", "", "description of the player (default from config)")
+	flags.StringVar(&g.target, "target", "", "nick of the opponent waiting in the lobby (turns off -bot)")
+	flags.BoolVar(&g.wpbot, "bot", false, "play against the server bot, -bot=false opens the lobby (default from config)")
+	flags.StringVar(&g.fleetFile, "fleet-file", "", "file with the fleet (JSON array or coordinates separated by spaces)")
+	flags.BoolVar(&g.offline, "offline", false, "play against the local bot without any server")
+	flags.BoolVar(&g.avoidEdges, "avoid-edges", false, "keep ships of the random fleet away from the edges (default from config)")
+	flags.StringVar(&g.corner, "corner", "", "cluster ships of the random fleet in the corner: A1, J1, A10 or J10 (default from config)")
+}
+
+// load reads the config and overrides it with the flags that were given explicitly.
+//
+//	Returns:
+//
+// config.Config - Validated configuration.
+//
+// error - If config can't be read or is not valid (eg. -target together with -bot).
+func (g *gameFlags) load(flags *flag.FlagSet) (config.Config, error) {
+	cfg, err := config.Load(g.configPath)
+	if err != nil {
+		return cfg, err
+	}
+	g.serverFlags.override(flags, &cfg)
+
+	botSet := false
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "nick":
+			cfg.Profile.Nick = g.nick
+		case "desc":
+			cfg.Profile.Desc = g.desc
+		case "target":
+			cfg.Profile.TargetNick = g.target
+		case "bot":
+			cfg.Profile.WPBot = g.wpbot
+			botSet = true
+		case "avoid-edges":
+			cfg.RandomFleet.AvoidEdges = g.avoidEdges
+		case "corner":
+			cfg.RandomFleet.Corner = g.corner
+		}
+	})
+
+	//Target means playing against the player, unless -bot was given explicitly
+	if g.target != "" && !botSet {
+		cfg.Profile.WPBot = false
+	}
+	return cfg, cfg.Validate()
+}
+
+// request builds the request that starts the game.
+//
+//	Returns:
+//
+// models.StartGameRequest - Profile of the player (coords are empty without -fleet-file).
+//
+// error - If fleet file is not valid.
+func (g *gameFlags) request(cfg config.Config) (models.StartGameRequest, error) {
+	request := models.StartGameRequest{
+		Nick:       cfg.Profile.Nick,
+		Desc:       cfg.Profile.Desc,
+		TargetNick: cfg.Profile.TargetNick,
+		WPBot:      cfg.Profile.WPBot,
+	}
+
+	if g.fleetFile != "" {
+		coords, err := util.LoadFleet(g.fleetFile)
+		if err != nil {
+			return request, err
+		}
+		request.Coords = coords
+	}
+	return request, nil
+}
+
+// backend creates the backend chosen by the flags.
+func (g *gameFlags) backend(cfg config.Config) source.Backend {
+	if g.offline {
+		return source.NewLocalBackend()
+	}
+	return source.NewRemoteBackend(newClient(cfg))
+}
+
+// signalContext returns context cancelled by Ctrl+C.
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// ----- COMMANDS ---------------------------------------------------------------------
+
+// runPlay plays the game in the terminal GUI.
+func runPlay(args []string) error {
+	flags := newFlagSet("play", "[flags]")
+	var game gameFlags
+	game.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	}
+
+	cfg, err := game.load(flags)
+	if err != nil {
+		return err
+	}
+	request, err := game.request(cfg)
+	if err != nil {
+		return err
+	}
+	if err := source.SetColors(cfg.Colors); err != nil {
+		return err
+	}
+	source.SetDefaultFleet(cfg.Fleet)
+	source.SetFleetOptions(cfg.RandomFleet.Options())
+	source.SetClient(newClient(cfg))
+	source.SetPollInterval(time.Duration(cfg.Server.PollInterval))
+	return source.BeginGame(game.backend(cfg), request)
+}
+
+// runBot lets the bot play without the GUI, logs the progress and prints the result as JSON.
+func runBot(args []string) error {
+	flags := newFlagSet("bot", "[flags]")
+	var game gameFlags
+	game.register(flags)
+	record := flags.String("record", "", "file to save the game record in (see replay)")
+	poll := flags.Duration("poll", 0, "time between two checks of the game status (default from config)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	}
+
+	cfg, err := game.load(flags)
+	if err != nil {
+		return err
+	}
+	request, err := game.request(cfg)
+	if err != nil {
+		return err
+	}
+	if len(request.Coords) == 0 {
+		request.Coords = cfg.Fleet
+	}
+	if *poll <= 0 {
+		*poll = time.Duration(cfg.Server.PollInterval)
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	result, err := bot.Play(ctx, game.backend(cfg), bot.Options{Request: request, Fleet: cfg.RandomFleet.Options(), PollInterval: *poll})
+	if err != nil {
+		return err
+	}
+	if *record != "" {
+		if err := bot.SaveResult(*record, result); err != nil {
+			return err
+		}
+	}
+
+	summary, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(summary))
+	return nil
+}
+
+// runLobby lists players waiting for the game.
+func runLobby(args []string) error {
+	flags := newFlagSet("lobby", "[flags]")
+	var remote serverFlags
+	remote.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	}
+
+	cfg, err := remote.load(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	lobby, response := newClient(cfg).Lobby(ctx)
+	if response.Err != nil {
+		return response.Err
+	}
+	if len(lobby) == 0 {
+		fmt.Println("Nobody is waiting in the lobby.")
+		return nil
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NICK\tSTATUS")
+	for _, player := range lobby {
+		fmt.Fprintf(table, "%s\t%s\n", player.Nick, player.GameStatus)
+	}
+	return table.Flush()
+}
+
+// runStats shows the leaderboard or statistics of the single player.
+func runStats(args []string) error {
+	flags := newFlagSet("stats", "[flags] [nick]")
+	var remote serverFlags
+	remote.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return usageError(flags, "expected at most one nick, got %d arguments", flags.NArg())
+	}
+
+	cfg, err := remote.load(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	c := newClient(cfg)
+	stats := []models.PlayerStats{}
+	if nick := flags.Arg(0); nick != "" {
+		player, response := c.StatsOfPlayer(ctx, nick)
+		if response.Err != nil {
+			return response.Err
+		}
+		stats = append(stats, player.Stats)
+	} else {
+		top, response := c.Stats(ctx)
+		if response.Err != nil {
+			return response.Err
+		}
+		stats = top.Stats
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RANK\tNICK\tGAMES\tWINS\tPOINTS")
+	for _, player := range stats {
+		fmt.Fprintf(table, "%d\t%s\t%d\t%d\t%d\n", player.Rank, player.Nick, player.Games, player.Wins, player.Points)
+	}
+	return table.Flush()
+}
+
+// runReplay replays the game recorded by the bot command.
+func runReplay(args []string) error {
+	flags := newFlagSet("replay", "[flags] <file>")
+	delay := flags.Duration("delay", 0, "pause between two moves (eg. 500ms)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags, "expected exactly one file")
+	}
+
+	result, err := bot.LoadResult(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	fmt.Printf("%s vs %s (%s)\n\n", result.Nick, result.Opponent, result.Outcome)
+	for i, move := range result.Moves {
+		fmt.Printf("%3d. %-3s %s\n", i+1, move.Coord, move.Result)
+		if *delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(*delay):
+			}
+		}
+	}
+
+	fmt.Println()
+	printBoards(result)
+	fmt.Printf("\n%d shots, %d hits, %d sunk, opponent fired %d times\n", len(result.Moves), result.Hits, result.Sunk, len(result.OppShots))
+	return nil
+}
+
+// runServe runs the local game server until Ctrl+C.
+func runServe(args []string) error {
+	flags := newFlagSet("serve", "[flags]")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	fmt.Printf("Local server is listening on %s (API under %s)\n", *addr, server.APIPrefix)
+	return server.ListenAndServe(ctx, *addr)
+}
+
+// runConfig shows the configuration in use or writes the default config file.
+func runConfig(args []string) error {
+	flags := newFlagSet("config", "[flags]")
+	path := flags.String("config", "", "config file (default $"+config.EnvConfig+" or config.json in the user's config directory)")
+	initialize := flags.Bool("init", false, "write the default config file if it does not exist")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	}
+
+	if *path == "" {
+		var err error
+		if *path, err = config.DefaultPath(); err != nil {
+			return err
+		}
+	}
+
+	if *initialize {
+		if _, err := os.Stat(*path); err == nil {
+			return fmt.Errorf("%s already exists", *path)
+		}
+		if err := config.Default().Save(*path); err != nil {
+			return err
+		}
+		fmt.Println("Default config written to", *path)
+		return nil
+	}
+
+	cfg, err := config.Load(*path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err != nil {
+		cfg = config.Default()
+		if err := cfg.ApplyEnv(); err != nil {
+			return err
+		}
+		fmt.Printf("# %s does not exist, defaults are used\n", *path)
+	} else {
+		fmt.Printf("# %s\n", *path)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// ----- HELPERS ----------------------------------------------------------------------
+
+// printBoards prints both boards after the recorded game side by side.
+//
+// Player's board: '#' ship, 'X' hit ship, '.' miss.
+// Opponent's board: 'X' hit, '.' miss.
+func printBoards(result bot.Result) {
+	var own, enemy [10][10]byte
+	for x := range own {
+		for y := range own[x] {
+			own[x][y], enemy[x][y] = ' ', ' '
+		}
+	}
+
+	mark := func(board *[10][10]byte, coord string, char byte) {
+		if x, y, err := util.CoordToIntegers(coord); err == nil && x >= 1 && x <= 10 && y >= 1 && y <= 10 {
+			board[x-1][y-1] = char
+		}
+	}
+	for _, coord := range result.Fleet {
+		mark(&own, coord, '#')
+	}
+	for _, coord := range result.OppShots {
+		if x, y, err := util.CoordToIntegers(coord); err == nil && x >= 1 && x <= 10 && y >= 1 && y <= 10 && own[x-1][y-1] == '#' {
+			own[x-1][y-1] = 'X'
+		} else {
+			mark(&own, coord, '.')
+		}
+	}
+	for _, move := range result.Moves {
+		char := byte('X')
+		if move.Result == models.ResultMiss {
+			char = '.'
+		}
+		mark(&enemy, move.Coord, char)
+	}
+
+	header := "    A B C D E F G H I J"
+	fmt.Printf("%-26s%s\n", "Your board", "Opponent's board")
+	fmt.Printf("%-26s%s\n", header, header)
+	for y := 0; y < 10; y++ {
+		line := strings.Builder{}
+		for _, board := range []*[10][10]byte{&own, &enemy} {
+			row := fmt.Sprintf("%3d ", y+1)
+			for x := 0; x < 10; x++ {
+				row += string(board[x][y]) + " "
+			}
+			fmt.Fprintf(&line, "%-26s", row)
+		}
+		fmt.Println(strings.TrimRight(line.String(), " "))
+	}
+}
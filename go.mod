@@ -3,9 +3,12 @@ module sea-of-pirates
 go 1.22.2
 
 require (
-	github.com/google/uuid v1.3.0 // indirect
-	github.com/grupawp/termloop v0.0.0-20230531144437-277a1cbf4c14 // indirect
-	github.com/grupawp/warships-gui/v2 v2.1.5 // indirect
+	github.com/google/uuid v1.3.0
+	github.com/grupawp/termloop v0.0.0-20230531144437-277a1cbf4c14
+	github.com/grupawp/warships-gui/v2 v2.1.5
+)
+
+require (
 	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/nsf/termbox-go v1.1.1 // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
@@ -1,7 +1,108 @@
 package main
 
-import source "sea-of-pirates/Source"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Exit codes of the program.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is a single subcommand of the command-line interface.
+//
+// name - Name typed after the program name.
+//
+// usage - Arguments of the command shown in the help text.
+//
+// summary - One line description of the command.
+//
+// run - Function that runs the command with the rest of the arguments.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+// commands are all the subcommands in order they are shown in the help text.
+var commands = []command{
+	{"play", "[flags]", "play the game in the terminal GUI", runPlay},
+	{"bot", "[flags]", "let the bot play without the GUI and print the result", runBot},
+	{"lobby", "[flags]", "list players waiting for the game", runLobby},
+	{"stats", "[flags] [nick]", "show the leaderboard or statistics of the player", runStats},
+	{"replay", "<file>", "replay the game recorded by bot --record", runReplay},
+	{"serve", "[flags]", "run the local game server", runServe},
+	{"config", "[flags]", "show the configuration or write the default config file", runConfig},
+}
+
+// errUsage is returned by commands when arguments are wrong (usage was already printed).
+var errUsage = errors.New("wrong usage")
 
 func main() {
-	source.BeginGame()
+	os.Exit(run(os.Args[1:]))
+}
+
+// run chooses the command and translates its error into the exit code.
+//
+//	Arguments:
+//
+// args - Arguments without the program name.
+//
+//	Returns:
+//
+// int - Exit code of the program.
+func run(args []string) int {
+	//Without command the game is played, like it always was
+	if len(args) == 0 {
+		args = []string{"play"}
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		printUsage()
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		err := cmd.run(args[1:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return exitOK
+		case errors.Is(err, errUsage):
+			return exitUsage
+		default:
+			fmt.Fprintf(os.Stderr, "sea-of-pirates %s: %v\n", name, err)
+			return exitFailure
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "sea-of-pirates: unknown command %q\n\n", name)
+	printUsage()
+	return exitUsage
+}
+
+// printUsage prints the help text with all the commands.
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Sea Of Pirates - battleships in the terminal.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  sea-of-pirates <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run \"sea-of-pirates <command> -help\" for the flags of the command.")
+	fmt.Fprintln(os.Stderr, "Without any command \"play\" is run.")
 }
@@ -0,0 +1,269 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ----- FLEET   ----------------------------------------------------------------------
+
+// ClassicFleet is the number of ships of every size in the classic 10x10 ruleset
+// (one 4-mast, two 3-mast, three 2-mast and four 1-mast ships).
+var ClassicFleet = map[int]int{4: 1, 3: 2, 2: 3, 1: 4}
+
+// Kinds of problems found by ValidateFleet.
+const (
+	FleetInvalidCoord = "invalid_coord"
+	FleetDuplicate    = "duplicate"
+	FleetTooLong      = "too_long"
+	FleetTouching     = "touching"
+	FleetWrongCount   = "wrong_count"
+)
+
+// FleetError is a single problem of the fleet.
+//
+// Kind - One of Fleet* constants.
+//
+// Cells - Coordinates of the offending cells (can be empty, eg. for missing ships).
+//
+// Message - Human readable description of the problem.
+type FleetError struct {
+	Kind    string
+	Cells   []string
+	Message string
+}
+
+func (e *FleetError) Error() string {
+	return e.Message
+}
+
+// FleetErrors are all the problems of the fleet found by ValidateFleet.
+type FleetErrors []*FleetError
+
+func (e FleetErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateFleet checks if coordinates make the classic fleet: cells are grouped into
+// ships by orthogonal connectivity, ships can't be longer than 4, number of ships
+// of every size must match ClassicFleet and ships can't touch each other (including
+// diagonals).
+//
+//	Arguments:
+//
+// coords - Coordinates of all the cells with ships (eg. "A1", "B10").
+//
+//	Returns:
+//
+// error - nil for the legal fleet, otherwise FleetErrors with every problem found.
+func ValidateFleet(coords []string) error {
+	problems := FleetErrors{}
+
+	//Reading the cells
+	cells := map[[2]int]bool{}
+	for _, coord := range coords {
+		x, y, err := CoordToIntegers(coord)
+		if err != nil || x < 1 || x > 10 || y < 1 || y > 10 {
+			problems = append(problems, &FleetError{FleetInvalidCoord, []string{coord}, "invalid coordinate " + coord})
+			continue
+		}
+		if cells[[2]int{x, y}] {
+			problems = append(problems, &FleetError{FleetDuplicate, []string{coord}, "coordinate " + coord + " is used more than once"})
+			continue
+		}
+		cells[[2]int{x, y}] = true
+	}
+
+	ships := groupCells(cells)
+
+	//Ships touching each other by corners
+	owner := map[[2]int]int{}
+	for i, ship := range ships {
+		for _, c := range ship {
+			owner[c] = i
+		}
+	}
+	for i, ship := range ships {
+		for _, c := range ship {
+			for _, d := range [][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+				other := [2]int{c[0] + d[0], c[1] + d[1]}
+				if j, ok := owner[other]; ok && j > i {
+					first, second := IntegersToCoord(c[0], c[1]), IntegersToCoord(other[0], other[1])
+					problems = append(problems, &FleetError{FleetTouching, []string{first, second}, "ships touch at " + first + " and " + second})
+				}
+			}
+		}
+	}
+
+	//Sizes and number of ships
+	bySize := map[int][][]string{}
+	for _, ship := range ships {
+		shipCoords := cellsToCoords(ship)
+		if len(ship) > 4 {
+			problems = append(problems, &FleetError{FleetTooLong, shipCoords, fmt.Sprintf("ship %s is too long (%d)", strings.Join(shipCoords, "-"), len(ship))})
+			continue
+		}
+		bySize[len(ship)] = append(bySize[len(ship)], shipCoords)
+	}
+	for size := 4; size >= 1; size-- {
+		if len(bySize[size]) == ClassicFleet[size] {
+			continue
+		}
+
+		offending := []string{}
+		if len(bySize[size]) > ClassicFleet[size] {
+			for _, ship := range bySize[size] {
+				offending = append(offending, ship...)
+			}
+		}
+		problems = append(problems, &FleetError{FleetWrongCount, offending,
+			fmt.Sprintf("need %d ship(s) of size %d, got %d", ClassicFleet[size], size, len(bySize[size]))})
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// ParseFleet reads the fleet written either as JSON array of coordinates or as
+// coordinates separated by whitespace or commas (eg. "A1 A2, B10").
+//
+//	Arguments:
+//
+// text - Written fleet.
+//
+//	Returns:
+//
+// []string - Coordinates of the fleet (upper case).
+//
+// error - If text can't be read or fleet is not legal (util.FleetErrors).
+func ParseFleet(text string) ([]string, error) {
+	coords := []string{}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") {
+		if err := json.Unmarshal([]byte(text), &coords); err != nil {
+			return nil, fmt.Errorf("fleet is not a JSON array of coordinates: %w", err)
+		}
+	} else {
+		coords = strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+
+	for i := range coords {
+		coords[i] = strings.ToUpper(strings.TrimSpace(coords[i]))
+	}
+	if err := ValidateFleet(coords); err != nil {
+		return nil, err
+	}
+	return coords, nil
+}
+
+// LoadFleet reads the fleet from the file (see ParseFleet for the format).
+//
+//	Arguments:
+//
+// path - Path of the file.
+//
+//	Returns:
+//
+// []string - Coordinates of the fleet.
+//
+// error - If file can't be read or fleet is not legal.
+func LoadFleet(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	coords, err := ParseFleet(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return coords, nil
+}
+
+// GroupShips joins orthogonally connected cells into separate ships.
+// Invalid and duplicated coordinates are skipped.
+//
+//	Arguments:
+//
+// coords - Coordinates of all the cells with ships (eg. "A1", "B10").
+//
+//	Returns:
+//
+// [][]string - Ships as sorted lists of coordinates.
+func GroupShips(coords []string) [][]string {
+	cells := map[[2]int]bool{}
+	for _, coord := range coords {
+		x, y, err := CoordToIntegers(coord)
+		if err == nil && x >= 1 && x <= 10 && y >= 1 && y <= 10 {
+			cells[[2]int{x, y}] = true
+		}
+	}
+
+	ships := [][]string{}
+	for _, ship := range groupCells(cells) {
+		ships = append(ships, cellsToCoords(ship))
+	}
+	return ships
+}
+
+// groupCells joins orthogonally connected cells into ships using flood fill.
+//
+//	Arguments:
+//
+// cells - Set of (x, y) cells with ships.
+//
+//	Returns:
+//
+// [][][2]int - Ships as sorted lists of cells, ships sorted by their first cell.
+func groupCells(cells map[[2]int]bool) [][][2]int {
+	visited := map[[2]int]bool{}
+	ships := [][][2]int{}
+
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			start := [2]int{x, y}
+			if !cells[start] || visited[start] {
+				continue
+			}
+
+			//Flood fill of the single ship
+			visited[start] = true
+			ship := [][2]int{start}
+			for i := 0; i < len(ship); i++ {
+				for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					next := [2]int{ship[i][0] + d[0], ship[i][1] + d[1]}
+					if cells[next] && !visited[next] {
+						visited[next] = true
+						ship = append(ship, next)
+					}
+				}
+			}
+
+			sort.Slice(ship, func(i, j int) bool {
+				return ship[i][0] < ship[j][0] || (ship[i][0] == ship[j][0] && ship[i][1] < ship[j][1])
+			})
+			ships = append(ships, ship)
+		}
+	}
+	return ships
+}
+
+// cellsToCoords translates (x, y) cells into coordinates (eg. "B10").
+func cellsToCoords(cells [][2]int) []string {
+	coords := make([]string, len(cells))
+	for i, c := range cells {
+		coords[i] = IntegersToCoord(c[0], c[1])
+	}
+	return coords
+}
@@ -0,0 +1,134 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// classicFleet is a legal fleet used by the tests.
+var classicFleet = []string{
+	"A1", "A2", "A3", "A4",
+	"D1", "D2", "D3", "F1", "F2", "F3",
+	"H1", "H2", "J1", "J2", "A6", "A7",
+	"C6", "E6", "G6", "I6",
+}
+
+// withCells returns the classic fleet without the removed and with the added cells.
+func withCells(removed []string, added ...string) []string {
+	coords := []string{}
+	for _, coord := range classicFleet {
+		skip := false
+		for _, r := range removed {
+			skip = skip || r == coord
+		}
+		if !skip {
+			coords = append(coords, coord)
+		}
+	}
+	return append(coords, added...)
+}
+
+func TestValidateFleet(t *testing.T) {
+	tests := []struct {
+		name   string
+		coords []string
+		kinds  []string
+	}{
+		{"classic", classicFleet, nil},
+		{"bent ship", withCells([]string{"A4"}, "B1"), nil},
+		{"lower case", withCells([]string{"C6"}, "c6"), nil},
+		{"touching by corner", withCells([]string{"C6"}, "B8"), []string{FleetTouching}},
+		{"invalid coordinate", withCells([]string{"C6"}, "K1"), []string{FleetInvalidCoord, FleetWrongCount}},
+		{"outside of the board", withCells([]string{"C6"}, "A11"), []string{FleetInvalidCoord, FleetWrongCount}},
+		{"duplicate", withCells(nil, "A1"), []string{FleetDuplicate}},
+		{"too long", withCells([]string{"C6"}, "A5"), []string{FleetTooLong, FleetWrongCount, FleetWrongCount, FleetWrongCount}},
+		{"missing ship", withCells([]string{"C6"}), []string{FleetWrongCount}},
+		{"empty", nil, []string{FleetWrongCount, FleetWrongCount, FleetWrongCount, FleetWrongCount}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateFleet(test.coords)
+			if test.kinds == nil {
+				if err != nil {
+					t.Fatalf("ValidateFleet() = %v, want nil", err)
+				}
+				return
+			}
+
+			var problems FleetErrors
+			if !errors.As(err, &problems) {
+				t.Fatalf("ValidateFleet() = %v, want FleetErrors", err)
+			}
+			kinds := []string{}
+			for _, problem := range problems {
+				kinds = append(kinds, problem.Kind)
+			}
+			if !reflect.DeepEqual(kinds, test.kinds) {
+				t.Fatalf("ValidateFleet() kinds = %v, want %v (%v)", kinds, test.kinds, err)
+			}
+		})
+	}
+}
+
+func TestValidateFleetCells(t *testing.T) {
+	err := ValidateFleet(withCells([]string{"C6"}, "B8"))
+	var problems FleetErrors
+	if !errors.As(err, &problems) || len(problems) != 1 {
+		t.Fatalf("ValidateFleet() = %v, want single problem", err)
+	}
+	if want := []string{"A7", "B8"}; !reflect.DeepEqual(problems[0].Cells, want) {
+		t.Fatalf("touching cells = %v, want %v", problems[0].Cells, want)
+	}
+}
+
+func TestParseFleet(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{"JSON", `["A1","A2","A3","A4","D1","D2","D3","F1","F2","F3","H1","H2","J1","J2","A6","A7","C6","E6","G6","I6"]`, classicFleet, false},
+		{"spaces and commas", "a1 a2, a3,a4\nd1 d2 d3 f1 f2 f3\th1 h2 j1 j2 a6 a7 c6 e6 g6 i6", classicFleet, false},
+		{"broken JSON", `["A1",`, nil, true},
+		{"illegal fleet", "A1 A2 A3", nil, true},
+		{"empty", "", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			coords, err := ParseFleet(test.text)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseFleet() error = %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(coords, test.want) {
+				t.Fatalf("ParseFleet() = %v, want %v", coords, test.want)
+			}
+		})
+	}
+}
+
+func TestGroupShips(t *testing.T) {
+	tests := []struct {
+		name   string
+		coords []string
+		want   [][]string
+	}{
+		{"single cell", []string{"B2"}, [][]string{{"B2"}}},
+		{"straight ship", []string{"C3", "A3", "B3"}, [][]string{{"A3", "B3", "C3"}}},
+		{"bent ship", []string{"A1", "A2", "B2"}, [][]string{{"A1", "A2", "B2"}}},
+		{"diagonal cells are separate", []string{"A1", "B2"}, [][]string{{"A1"}, {"B2"}}},
+		{"invalid and duplicated skipped", []string{"A1", "A1", "K5", "", "A11"}, [][]string{{"A1"}}},
+		{"empty", nil, [][]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if ships := GroupShips(test.coords); !reflect.DeepEqual(ships, test.want) {
+				t.Fatalf("GroupShips() = %v, want %v", ships, test.want)
+			}
+		})
+	}
+}
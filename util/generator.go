@@ -0,0 +1,389 @@
+package util
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// ----- GENERATOR --------------------------------------------------------------------
+
+// Corners of the board that can be used by FleetOptions.Corner.
+const (
+	CornerTopLeft     = "A1"
+	CornerTopRight    = "J1"
+	CornerBottomLeft  = "A10"
+	CornerBottomRight = "J10"
+)
+
+// avoidEdgesLimit is the maximal number of cells on the edges of the board when
+// FleetOptions.AvoidEdges is set.
+const avoidEdgesLimit = 4
+
+// cornerClusterMin is the minimal number of cells inside of the 5x5 quarter of the
+// board when FleetOptions.Corner is set.
+const cornerClusterMin = 9
+
+// maxFleetAttempts is the number of times the placement of the constrained fleet
+// starts from scratch (when no position is left for the next ship) before giving up.
+const maxFleetAttempts = 100
+
+// shipPlacements are all the positions of the ships on the board by their size,
+// including the bent ones (every shape accepted by ValidateFleet).
+var shipPlacements = allPlacements()
+
+// FleetOptions configure RandomFleet.
+//
+// Seed - Seed of the randomness for reproducible fleets (0 means random seed).
+//
+// AvoidEdges - Keep ships away from the edges of the board (at most 4 cells on edges).
+//
+// Corner - Cluster ships in the given corner (one of Corner* constants, empty for none).
+type FleetOptions struct {
+	Seed       int64
+	AvoidEdges bool
+	Corner     string
+}
+
+// Validate checks if the constraints are known.
+//
+//	Returns:
+//
+// error - If corner is not one of Corner* constants.
+func (o FleetOptions) Validate() error {
+	_, err := cornerFilter(o.Corner)
+	return err
+}
+
+// RandomFleet generates random legal classic fleet (ships can be bent, as long as
+// ValidateFleet accepts them).
+//
+// Without constraints the fleet is uniformly random: every ship is put at one of all
+// its positions and the whole fleet is drawn again until no ships touch each other.
+// Constrained fleets are too rare for that, so their ships are placed one by one,
+// from the biggest, at the random position that keeps the constraints reachable
+// (inside of the corner until it is filled). Four of five placements succeed, so
+// every combination of constraints is met, but such fleets are not uniformly
+// distributed.
+//
+//	Arguments:
+//
+// options - Seed and constraints of the fleet.
+//
+//	Returns:
+//
+// []string - Coordinates of all the cells with ships (eg. "A1", "B10").
+//
+// error - If constraints are unknown or fleet can't be found.
+func RandomFleet(options FleetOptions) ([]string, error) {
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	inCorner, err := cornerFilter(options.Corner)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.AvoidEdges && options.Corner == "" {
+		return cellsToCoords(sampleShips(random)), nil
+	}
+
+	budget := fleetBudget{avoidEdges: options.AvoidEdges, corner: options.Corner != "", inCorner: inCorner}
+	for attempt := 0; attempt < maxFleetAttempts; attempt++ {
+		if cells, ok := placeShips(random, budget); ok {
+			return cellsToCoords(cells), nil
+		}
+	}
+
+	return nil, errors.New("can't generate fleet with given constraints")
+}
+
+// sampleShips draws the uniformly random fleet. Every ship takes one of all its
+// positions on the board and the whole fleet is drawn again if ships touch, so every
+// legal fleet is equally likely. Roughly one of five thousand draws is legal, which
+// takes a few milliseconds.
+//
+//	Arguments:
+//
+// random - Source of randomness.
+//
+//	Returns:
+//
+// [][2]int - Cells of all the ships.
+func sampleShips(random *rand.Rand) [][2]int {
+	for {
+		//Board with margin, so neighbours of the edge cells can be checked
+		var occupied [12][12]bool
+		cells := [][2]int{}
+
+		legal := true
+		for size := 4; size >= 1 && legal; size-- {
+			for count := 0; count < ClassicFleet[size] && legal; count++ {
+				ship := shipPlacements[size][random.Intn(len(shipPlacements[size]))]
+				if legal = !touches(&occupied, ship); legal {
+					occupy(&occupied, ship)
+					cells = append(cells, ship...)
+				}
+			}
+		}
+
+		if legal {
+			return cells
+		}
+	}
+}
+
+// fleetBudget tracks the constraints of FleetOptions while ships are being placed.
+//
+// avoidEdges, corner - Which constraints are used.
+//
+// inCorner - Filter of the cells inside of the chosen corner.
+//
+// edges, cornerCells - Cells of the placed ships on the edges and inside of the corner.
+type fleetBudget struct {
+	avoidEdges  bool
+	corner      bool
+	inCorner    func([2]int) bool
+	edges       int
+	cornerCells int
+}
+
+// allows checks if the ship can be placed, so that the constraints can still be met.
+//
+//	Arguments:
+//
+// ship - Cells of the ship.
+//
+// left - Number of cells of the ships placed after this one.
+//
+//	Returns:
+//
+// bool - True if ship fits into the constraints.
+func (b *fleetBudget) allows(ship [][2]int, left int) bool {
+	edges, corner := b.count(ship)
+	if b.avoidEdges && b.edges+edges > avoidEdgesLimit {
+		return false
+	}
+	return !b.corner || b.cornerCells+corner+left >= cornerClusterMin
+}
+
+// add counts the cells of the placed ship.
+func (b *fleetBudget) add(ship [][2]int) {
+	edges, corner := b.count(ship)
+	b.edges += edges
+	b.cornerCells += corner
+}
+
+// short checks if the corner still needs more cells.
+func (b *fleetBudget) short() bool {
+	return b.corner && b.cornerCells < cornerClusterMin
+}
+
+// count returns the number of cells of the ship on the edges and inside of the corner.
+func (b *fleetBudget) count(ship [][2]int) (int, int) {
+	edges, corner := 0, 0
+	for _, c := range ship {
+		if c[0] == 1 || c[0] == 10 || c[1] == 1 || c[1] == 10 {
+			edges++
+		}
+		if b.inCorner(c) {
+			corner++
+		}
+	}
+	return edges, corner
+}
+
+// placeShips places every ship of ClassicFleet at random position where it does not
+// touch other ships and the constraints can still be met. Ships fill the corner first.
+//
+//	Arguments:
+//
+// random - Source of randomness.
+//
+// budget - Constraints of the fleet (copied, so it can be reused).
+//
+//	Returns:
+//
+// [][2]int - Cells of all the ships.
+//
+// bool - False if some ship could not be placed anywhere.
+func placeShips(random *rand.Rand, budget fleetBudget) ([][2]int, bool) {
+	//Board with margin, so neighbours of the edge cells can be checked
+	var occupied [12][12]bool
+	cells := [][2]int{}
+
+	left := 0
+	for size, count := range ClassicFleet {
+		left += size * count
+	}
+
+	for size := 4; size >= 1; size-- {
+		for count := 0; count < ClassicFleet[size]; count++ {
+			left -= size
+
+			//Until the corner is filled, ships go whole into it (if they can)
+			positions, inside := [][][2]int{}, [][][2]int{}
+			for _, ship := range shipPlacements[size] {
+				if touches(&occupied, ship) || !budget.allows(ship, left) {
+					continue
+				}
+				positions = append(positions, ship)
+				if _, corner := budget.count(ship); corner == size && budget.short() {
+					inside = append(inside, ship)
+				}
+			}
+			if len(inside) > 0 {
+				positions = inside
+			}
+			if len(positions) == 0 {
+				return nil, false
+			}
+
+			ship := positions[random.Intn(len(positions))]
+			occupy(&occupied, ship)
+			budget.add(ship)
+			cells = append(cells, ship...)
+		}
+	}
+
+	return cells, true
+}
+
+// allPlacements finds every position of the ships of sizes 1-4 on the board.
+//
+//	Returns:
+//
+// map[int][][][2]int - Cells of every placed ship by its size.
+func allPlacements() map[int][][][2]int {
+	placements := map[int][][][2]int{}
+	for size, shapes := range ShipShapes(4) {
+		for _, shape := range shapes {
+			for x := 1; x <= 10; x++ {
+				for y := 1; y <= 10; y++ {
+					ship := make([][2]int, len(shape))
+					fits := true
+					for i, c := range shape {
+						ship[i] = [2]int{c[0] + x, c[1] + y}
+						fits = fits && ship[i][0] <= 10 && ship[i][1] <= 10
+					}
+					if fits {
+						placements[size] = append(placements[size], ship)
+					}
+				}
+			}
+		}
+	}
+	return placements
+}
+
+// ShipShapes finds all the shapes of the ships (cells connected by their sides) up
+// to the given size. Every rotation and reflection is a separate shape, cells start
+// at (0, 0) and shapes are sorted, so the placements are always in the same order.
+//
+//	Arguments:
+//
+// maxSize - The biggest size of the ship.
+//
+//	Returns:
+//
+// map[int][][][2]int - Shapes of the ships by their size.
+func ShipShapes(maxSize int) map[int][][][2]int {
+	shapes := map[int][][][2]int{1: {{{0, 0}}}}
+	for size := 2; size <= maxSize; size++ {
+		found := map[string][][2]int{}
+		for _, shape := range shapes[size-1] {
+			for _, c := range shape {
+				for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					grown := normalizeShape(append(slices.Clone(shape), [2]int{c[0] + d[0], c[1] + d[1]}))
+					if len(grown) == size {
+						found[fmt.Sprint(grown)] = grown
+					}
+				}
+			}
+		}
+
+		keys := []string{}
+		for key := range found {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			shapes[size] = append(shapes[size], found[key])
+		}
+	}
+	return shapes
+}
+
+// normalizeShape removes repeated cells, moves the shape to (0, 0) and sorts its cells.
+func normalizeShape(shape [][2]int) [][2]int {
+	minX, minY := shape[0][0], shape[0][1]
+	for _, c := range shape {
+		minX, minY = min(minX, c[0]), min(minY, c[1])
+	}
+
+	normalized := [][2]int{}
+	for _, c := range shape {
+		moved := [2]int{c[0] - minX, c[1] - minY}
+		if !slices.Contains(normalized, moved) {
+			normalized = append(normalized, moved)
+		}
+	}
+	slices.SortFunc(normalized, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	return normalized
+}
+
+// touches checks if ship overlaps or touches any of the occupied cells.
+func touches(occupied *[12][12]bool, ship [][2]int) bool {
+	for _, c := range ship {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if occupied[c[0]+dx][c[1]+dy] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// occupy marks the cells of the ship on the board.
+func occupy(occupied *[12][12]bool, ship [][2]int) {
+	for _, c := range ship {
+		occupied[c[0]][c[1]] = true
+	}
+}
+
+// cornerFilter returns function checking if cell is inside of the 5x5 quarter of the given corner.
+//
+//	Arguments:
+//
+// corner - One of Corner* constants (empty means no corner).
+//
+//	Returns:
+//
+// func([2]int) bool - Filter of the cells.
+//
+// error - If corner is unknown.
+func cornerFilter(corner string) (func([2]int) bool, error) {
+	switch corner {
+	case "":
+		return func([2]int) bool { return false }, nil
+	case CornerTopLeft:
+		return func(c [2]int) bool { return c[0] <= 5 && c[1] <= 5 }, nil
+	case CornerTopRight:
+		return func(c [2]int) bool { return c[0] > 5 && c[1] <= 5 }, nil
+	case CornerBottomLeft:
+		return func(c [2]int) bool { return c[0] <= 5 && c[1] > 5 }, nil
+	case CornerBottomRight:
+		return func(c [2]int) bool { return c[0] > 5 && c[1] > 5 }, nil
+	}
+	return nil, fmt.Errorf("unknown corner %q (use %s, %s, %s or %s)", corner, CornerTopLeft, CornerTopRight, CornerBottomLeft, CornerBottomRight)
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fleetOptions are the combinations of the constraints used by the tests.
+var fleetOptions = []struct {
+	name    string
+	options FleetOptions
+}{
+	{"no constraints", FleetOptions{}},
+	{"avoid edges", FleetOptions{AvoidEdges: true}},
+	{"top left corner", FleetOptions{Corner: CornerTopLeft}},
+	{"top right corner", FleetOptions{Corner: CornerTopRight}},
+	{"bottom left corner", FleetOptions{Corner: CornerBottomLeft}},
+	{"bottom right corner", FleetOptions{Corner: CornerBottomRight}},
+	{"avoid edges in top right corner", FleetOptions{AvoidEdges: true, Corner: CornerTopRight}},
+	{"avoid edges in bottom left corner", FleetOptions{AvoidEdges: true, Corner: CornerBottomLeft}},
+}
+
+// fleetSeeds is the number of seeds tried for every combination.
+const fleetSeeds = 200
+
+func TestRandomFleet(t *testing.T) {
+	for _, test := range fleetOptions {
+		t.Run(test.name, func(t *testing.T) {
+			inCorner, err := cornerFilter(test.options.Corner)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for seed := int64(1); seed <= fleetSeeds; seed++ {
+				options := test.options
+				options.Seed = seed
+				coords, err := RandomFleet(options)
+				if err != nil {
+					t.Fatalf("seed %d: RandomFleet() error = %v", seed, err)
+				}
+				if err := ValidateFleet(coords); err != nil {
+					t.Fatalf("seed %d: illegal fleet %v: %v", seed, coords, err)
+				}
+
+				edges, corner := 0, 0
+				for _, coord := range coords {
+					x, y, _ := CoordToIntegers(coord)
+					if x == 1 || x == 10 || y == 1 || y == 10 {
+						edges++
+					}
+					if inCorner([2]int{x, y}) {
+						corner++
+					}
+				}
+				if options.AvoidEdges && edges > avoidEdgesLimit {
+					t.Fatalf("seed %d: %d cells on the edges of %v", seed, edges, coords)
+				}
+				if options.Corner != "" && corner < cornerClusterMin {
+					t.Fatalf("seed %d: %d cells in the corner %s of %v", seed, corner, options.Corner, coords)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomFleetSeed(t *testing.T) {
+	for _, test := range fleetOptions {
+		t.Run(test.name, func(t *testing.T) {
+			options := test.options
+			options.Seed = 42
+			first, err := RandomFleet(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := RandomFleet(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("same seed gave %v and %v", first, second)
+			}
+
+			options.Seed = 43
+			if other, err := RandomFleet(options); err != nil || reflect.DeepEqual(first, other) {
+				t.Fatalf("other seed gave %v (%v), want other fleet than %v", other, err, first)
+			}
+		})
+	}
+}
+
+func TestRandomFleetBentShips(t *testing.T) {
+	for seed := int64(1); seed <= fleetSeeds; seed++ {
+		coords, err := RandomFleet(FleetOptions{Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ship := range GroupShips(coords) {
+			x, y := map[string]bool{}, map[string]bool{}
+			for _, coord := range ship {
+				x[coord[:1]], y[coord[1:]] = true, true
+			}
+			if len(x) > 1 && len(y) > 1 {
+				return
+			}
+		}
+	}
+	t.Fatalf("no bent ship in %d fleets", fleetSeeds)
+}
+
+func TestRandomFleetUnknownCorner(t *testing.T) {
+	if coords, err := RandomFleet(FleetOptions{Corner: "E5"}); err == nil {
+		t.Fatalf("RandomFleet() = %v, want error for unknown corner", coords)
+	}
+}
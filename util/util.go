@@ -55,6 +55,22 @@ func CoordToIntegers(coords string) (int, int, error) {
 	return int(rune(letter[0]) - 96), newNumbers, nil
 }
 
+// Function that translates two numbers (like 2 and 10) to coords (like 'B10').
+// It is the opposite of CoordToIntegers.
+//
+//	Arguments:
+//
+// x - Number of the letter (1 is 'A').
+//
+// y - Number of the row.
+//
+//	Returns:
+//
+// string - Coordinate (eg. "B10")
+func IntegersToCoord(x int, y int) string {
+	return string(rune('A'+x-1)) + strconv.Itoa(y)
+}
+
 // ----- TEXTS   ----------------------------------------------------------------------
 
 // DrawText is a function that prints out text using standard FMT library.
@@ -72,6 +88,51 @@ func DrawText(str string, newLine bool) {
 	}
 }
 
+// WrapText splits the text into lines not longer than width, breaking at spaces.
+// Words longer than width are split as well.
+//
+//	Arguments:
+//
+// str - Text to be wrapped.
+//
+// width - Maximal length of the line.
+//
+//	Returns:
+//
+// []string - Lines of the text (empty for the blank text).
+func WrapText(str string, width int) []string {
+	lines := []string{}
+	if width <= 0 {
+		return lines
+	}
+	line := ""
+	for _, word := range strings.Fields(str) {
+		//Splitting words that never fit into the line
+		for len([]rune(word)) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string([]rune(word)[:width]))
+			word = string([]rune(word)[width:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // ----- ERRORS -----------------------------------------------------------------------
 
 // errorCreate creates simple error with given text inside of it.